@@ -2,7 +2,8 @@ package testutils
 
 import "net/http"
 
-// MockClientService is used to allow mocking the HTTP client when testing
+// MockClientService is used to allow mocking the HTTP client when testing.
+// It is satisfied by every service implementing types.HTTPClientService.
 type MockClientService interface {
 	GetHTTPClient() *http.Client
 }
@@ -1,6 +1,7 @@
 package testutils_test
 
 import (
+	"context"
 	"net/url"
 	"testing"
 
@@ -130,3 +131,6 @@ type dummyService struct {
 
 func (s *dummyService) Initialize(_ *url.URL, _ types.StdLogger) error { return nil }
 func (s *dummyService) Send(_ string, _ *types.Params) error           { return nil }
+func (s *dummyService) SendContext(_ context.Context, _ string, _ *types.Params) error {
+	return nil
+}
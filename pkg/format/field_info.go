@@ -18,6 +18,7 @@ type FieldInfo struct {
 	DefaultValue  string
 	Template      string
 	Required      bool
+	Secret        bool
 	URLParts      []URLPart
 	Title         bool
 	Base          int
@@ -30,6 +31,12 @@ func (fi *FieldInfo) IsEnum() bool {
 	return fi.EnumFormatter != nil && fi.Type.Kind() == r.Int
 }
 
+// IsSecret returns whether the field value is a secret, like a password or token, that should not be shown. Fields
+// tagged with `secret` are secret, as well as any field serialized as the URL password.
+func (fi *FieldInfo) IsSecret() bool {
+	return fi.Secret || fi.IsURLPart(URLPassword)
+}
+
 // IsURLPart returns whether the field is serialized as the specified part of an URL
 func (fi *FieldInfo) IsURLPart(part URLPart) bool {
 	for _, up := range fi.URLParts {
@@ -83,6 +90,10 @@ func getStructFieldInfo(structType r.Type, enums map[string]types.EnumFormatter)
 			info.Required = false
 		}
 
+		if _, ok := fieldDef.Tag.Lookup("secret"); ok {
+			info.Secret = true
+		}
+
 		if _, ok := fieldDef.Tag.Lookup("title"); ok {
 			info.Title = true
 		}
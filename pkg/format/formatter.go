@@ -33,6 +33,19 @@ func GetServiceConfig(service types.Service) types.ServiceConfig {
 	return ourRef.Interface().(types.ServiceConfig)
 }
 
+// HasServiceConfig returns whether the service has an inner config that can be returned by GetServiceConfig
+func HasServiceConfig(service types.Service) bool {
+	serviceValue := r.Indirect(r.ValueOf(service))
+	if serviceValue.Kind() != r.Struct {
+		return false
+	}
+	configField, found := serviceValue.Type().FieldByName("config")
+	if !found || configField.Type.Kind() != r.Ptr {
+		return false
+	}
+	return configField.Type.Implements(r.TypeOf((*types.ServiceConfig)(nil)).Elem())
+}
+
 // ColorFormatTree returns a color highlighted string representation of a node tree
 func ColorFormatTree(rootNode *ContainerNode, withValues bool) string {
 	return ConsoleTreeRenderer{WithValues: withValues}.RenderTree(rootNode, "")
@@ -0,0 +1,136 @@
+package format
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// RedactedValue replaces the secrets in redacted strings, matching url.URL.Redacted
+const RedactedValue = "xxxxx"
+
+// minSecretLength is the length below which secret values are not replaced in arbitrary text, since they would match
+// too much of it
+const minSecretLength = 4
+
+// SecretValues returns the values of the secret fields of config that are set
+func SecretValues(config types.ServiceConfig) []string {
+	var secrets []string
+	for _, node := range GetConfigFormat(config).Items {
+		valueNode, isValue := node.(*ValueNode)
+		if !isValue || !node.Field().IsSecret() || valueNode.Value == "" {
+			continue
+		}
+		secrets = append(secrets, valueNode.Value)
+	}
+	return secrets
+}
+
+// HasSecretFields returns whether any of the fields of the config are secret
+func HasSecretFields(config types.ServiceConfig) bool {
+	for _, node := range GetConfigFormat(config).Items {
+		if node.Field().IsSecret() {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactConfigFormat replaces the values of the secret fields in the config tree
+func RedactConfigFormat(root *ContainerNode) {
+	for _, node := range root.Items {
+		if valueNode, isValue := node.(*ValueNode); isValue && node.Field().IsSecret() && valueNode.Value != "" {
+			valueNode.Value = RedactedValue
+		}
+	}
+}
+
+// RedactSecrets returns s with every occurrence of the secrets replaced, including their URL escaped forms
+func RedactSecrets(s string, secrets []string) string {
+	var replacements []string
+	for _, secret := range secrets {
+		if len(secret) < minSecretLength {
+			continue
+		}
+		replacements = append(replacements, secret)
+		if escaped := url.PathEscape(secret); escaped != secret {
+			replacements = append(replacements, escaped)
+		}
+		if escaped := url.QueryEscape(secret); escaped != secret {
+			replacements = append(replacements, escaped)
+		}
+	}
+
+	// Replace the longest secrets first, in case one of them contains another
+	sort.SliceStable(replacements, func(a, b int) bool {
+		return len(replacements[a]) > len(replacements[b])
+	})
+	for _, secret := range replacements {
+		s = strings.ReplaceAll(s, secret, RedactedValue)
+	}
+	return s
+}
+
+// RedactURL returns serviceURL as a string, with the URL parts and query values that the secret fields of config are
+// serialized to replaced, as well as the URL password and any other occurrence of the secret values
+func RedactURL(config types.ServiceConfig, serviceURL *url.URL) string {
+	redacted := *serviceURL
+	username := redacted.User.Username()
+	password, hasPassword := redacted.User.Password()
+	segments := strings.Split(strings.TrimPrefix(redacted.Path, "/"), "/")
+	query := redacted.Query()
+	pathRedacted, queryRedacted := false, false
+
+	for _, node := range GetConfigFormat(config).Items {
+		field := node.Field()
+		if !field.IsSecret() {
+			continue
+		}
+		for _, part := range field.URLParts {
+			switch {
+			case part == URLUser:
+				username = RedactedValue
+			case part == URLHost:
+				redacted.Host = redactHost(redacted.Host, redacted.Port())
+			case part >= URLPath:
+				if index := int(part - URLPath); index < len(segments) && segments[index] != "" {
+					segments[index] = RedactedValue
+					pathRedacted = true
+				}
+			}
+		}
+		for key := range query {
+			for _, fieldKey := range field.Keys {
+				if strings.EqualFold(key, fieldKey) {
+					query.Set(key, RedactedValue)
+					queryRedacted = true
+				}
+			}
+		}
+	}
+
+	if hasPassword {
+		password = RedactedValue
+		redacted.User = url.UserPassword(username, password)
+	} else if redacted.User != nil {
+		redacted.User = url.User(username)
+	}
+	if pathRedacted {
+		redacted.Path = "/" + strings.Join(segments, "/")
+		redacted.RawPath = ""
+	}
+	if queryRedacted {
+		redacted.RawQuery = query.Encode()
+	}
+
+	return RedactSecrets(redacted.String(), SecretValues(config))
+}
+
+func redactHost(host string, port string) string {
+	if port == "" {
+		return RedactedValue
+	}
+	return RedactedValue + ":" + port
+}
@@ -0,0 +1,178 @@
+package logsink
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// DefaultInterval is the time the log records are collected for, before they are sent as a single notification
+const DefaultInterval = 5 * time.Second
+
+// DefaultMaxItems is the maximum number of log records sent as a single notification
+const DefaultMaxItems = 20
+
+// DefaultMaxQueued is the maximum number of notifications waiting to be sent
+const DefaultMaxQueued = 50
+
+// Sender is the interface used to send the log records, implemented by router.ServiceRouter
+type Sender interface {
+	SendItems(items []types.MessageItem, params types.Params) []error
+}
+
+// Options are the batching and sending options used for the log records
+type Options struct {
+	// Params are passed to the services along with every notification
+	Params types.Params
+	// Interval is the time records are collected for before sending them, defaults to DefaultInterval
+	Interval time.Duration
+	// MaxItems is the number of records that causes the collected records to be sent right away, defaults to
+	// DefaultMaxItems
+	MaxItems int
+	// MaxQueued is the number of notifications that can be waiting to be sent, defaults to DefaultMaxQueued.
+	// The records of any notifications beyond it are dropped, and summarised in the next notification that is sent.
+	MaxQueued int
+	// ErrorHandler is called with the errors of a notification that could not be sent using any of the services.
+	// It should not log the errors using the same sink, since that would cause another notification.
+	ErrorHandler func(errs []error)
+}
+
+// Batcher collects message items, and sends them as notifications in the background, in the order they were added.
+// It is used by Writer and Handler, and can be used directly for items that are not log records.
+type Batcher struct {
+	sender  Sender
+	options Options
+
+	mutex   sync.Mutex
+	pending []types.MessageItem
+	timer   *time.Timer
+	queue   [][]types.MessageItem
+	dropped int
+	sending bool
+	sent    *sync.Cond
+}
+
+// NewBatcher returns a Batcher sending the items added to it using sender
+func NewBatcher(sender Sender, options *Options) *Batcher {
+	b := &Batcher{sender: sender}
+	b.sent = sync.NewCond(&b.mutex)
+	if options != nil {
+		b.options = *options
+	}
+	if b.options.Interval <= 0 {
+		b.options.Interval = DefaultInterval
+	}
+	if b.options.MaxItems <= 0 {
+		b.options.MaxItems = DefaultMaxItems
+	}
+	if b.options.MaxQueued <= 0 {
+		b.options.MaxQueued = DefaultMaxQueued
+	}
+	return b
+}
+
+// Add adds the item to the current batch, starting a new batch if there is none
+func (b *Batcher) Add(item types.MessageItem) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.pending = append(b.pending, item)
+	if len(b.pending) >= b.options.MaxItems {
+		b.enqueue()
+	} else if b.timer == nil {
+		b.timer = time.AfterFunc(b.options.Interval, func() {
+			b.mutex.Lock()
+			defer b.mutex.Unlock()
+			b.enqueue()
+		})
+	}
+}
+
+// Flush sends the current batch, and waits until every batch has been sent. Unlike Add, it waits for room in the
+// queue instead of dropping the batch if the queue is full.
+func (b *Batcher) Flush() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for len(b.queue) >= b.options.MaxQueued {
+		b.sent.Wait()
+	}
+	b.enqueue()
+	for b.sending {
+		b.sent.Wait()
+	}
+}
+
+// enqueue moves the current batch to the send queue, or drops it if the queue is full. The caller must hold the mutex.
+func (b *Batcher) enqueue() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.pending) == 0 && b.dropped == 0 {
+		return
+	}
+
+	if len(b.queue) >= b.options.MaxQueued {
+		b.dropped += len(b.pending)
+		b.pending = nil
+		return
+	}
+
+	if b.dropped > 0 {
+		summary := types.MessageItem{
+			Text:      fmt.Sprintf("%d items were dropped, since the notifications could not be sent fast enough", b.dropped),
+			Timestamp: time.Now(),
+			Level:     types.Warning,
+		}
+		b.pending = append([]types.MessageItem{summary}, b.pending...)
+		b.dropped = 0
+	}
+
+	b.queue = append(b.queue, b.pending)
+	b.pending = nil
+
+	if !b.sending {
+		b.sending = true
+		go b.drain()
+	}
+}
+
+// drain sends the queued batches until the queue is empty, waking up the callers of Flush after every batch
+func (b *Batcher) drain() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for len(b.queue) > 0 {
+		items := b.queue[0]
+		b.queue = b.queue[1:]
+
+		b.mutex.Unlock()
+		b.send(items)
+		b.mutex.Lock()
+
+		b.sent.Broadcast()
+	}
+	b.sending = false
+	b.sent.Broadcast()
+}
+
+func (b *Batcher) send(items []types.MessageItem) {
+	params := make(types.Params, len(b.options.Params))
+	for key, value := range b.options.Params {
+		params[key] = value
+	}
+
+	var errs []error
+	for _, err := range b.sender.SendItems(items, params) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 && b.options.ErrorHandler != nil {
+		b.options.ErrorHandler(errs)
+	}
+}
@@ -0,0 +1,116 @@
+//go:build go1.21
+
+package logsink
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// Handler is a slog.Handler that sends the records at or above its level as message items. The attributes of the
+// records are added as fields, with the names of any groups prepended to their keys, separated by a dot.
+type Handler struct {
+	batcher *Batcher
+	level   slog.Leveler
+	fields  []types.Field
+	prefix  string
+}
+
+var _ slog.Handler = &Handler{}
+
+// NewHandler returns a Handler sending the records at or above level using sender. If level is nil, records at
+// slog.LevelInfo and above are sent.
+func NewHandler(sender Sender, level slog.Leveler, options *Options) *Handler {
+	if level == nil {
+		level = slog.LevelInfo
+	}
+	return &Handler{
+		batcher: NewBatcher(sender, options),
+		level:   level,
+	}
+}
+
+// Enabled implements slog.Handler
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle implements slog.Handler, adding the record to the current batch
+func (h *Handler) Handle(_ context.Context, record slog.Record) error {
+	fields := make([]types.Field, len(h.fields), len(h.fields)+record.NumAttrs())
+	copy(fields, h.fields)
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = appendAttr(fields, h.prefix, attr)
+		return true
+	})
+
+	h.batcher.Add(types.MessageItem{
+		Text:      record.Message,
+		Timestamp: record.Time,
+		Level:     MessageLevel(record.Level),
+		Fields:    fields,
+	})
+	return nil
+}
+
+// WithAttrs implements slog.Handler
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.fields = make([]types.Field, len(h.fields), len(h.fields)+len(attrs))
+	copy(clone.fields, h.fields)
+	for _, attr := range attrs {
+		clone.fields = appendAttr(clone.fields, h.prefix, attr)
+	}
+	return &clone
+}
+
+// WithGroup implements slog.Handler
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// Flush sends the records that have not been sent yet, and waits for every notification to be sent
+func (h *Handler) Flush() {
+	h.batcher.Flush()
+}
+
+// MessageLevel returns the message level corresponding to the slog level
+func MessageLevel(level slog.Level) types.MessageLevel {
+	switch {
+	case level >= slog.LevelError:
+		return types.Error
+	case level >= slog.LevelWarn:
+		return types.Warning
+	case level >= slog.LevelInfo:
+		return types.Info
+	default:
+		return types.Debug
+	}
+}
+
+// appendAttr appends the attribute to fields, flattening any groups
+func appendAttr(fields []types.Field, prefix string, attr slog.Attr) []types.Field {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, groupAttr := range attr.Value.Group() {
+			fields = appendAttr(fields, prefix, groupAttr)
+		}
+		return fields
+	}
+
+	return append(fields, types.Field{Key: prefix + attr.Key, Value: attr.Value.String()})
+}
@@ -0,0 +1,61 @@
+//go:build go1.21
+
+package logsink_test
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/logsink"
+	"github.com/containrrr/shoutrrr/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("the slog handler", func() {
+	var sender *recordingSender
+	BeforeEach(func() {
+		sender = &recordingSender{}
+	})
+
+	It("should only send the records at or above the level", func() {
+		handler := logsink.NewHandler(sender, slog.LevelWarn, nil)
+		logger := slog.New(handler)
+		logger.Info("ignored")
+		logger.Warn("sent")
+		logger.Error("also sent")
+		handler.Flush()
+
+		Expect(texts(sender.Batches())).To(Equal([][]string{{"sent", "also sent"}}))
+	})
+	It("should map the record to a message item", func() {
+		handler := logsink.NewHandler(sender, nil, nil)
+		slog.New(handler).Error("backup failed", "job", "nightly", "attempt", 3)
+		handler.Flush()
+
+		Expect(sender.Batches()).To(HaveLen(1))
+		item := sender.Batches()[0][0]
+		Expect(item.Level).To(Equal(types.Error))
+		Expect(item.Timestamp).To(BeTemporally("~", time.Now(), time.Second))
+		Expect(item.Fields).To(Equal([]types.Field{{Key: "job", Value: "nightly"}, {Key: "attempt", Value: "3"}}))
+	})
+	It("should prefix the keys of grouped attributes with the group names", func() {
+		handler := logsink.NewHandler(sender, nil, nil)
+		logger := slog.New(handler).With("app", "backup").WithGroup("req").With("id", 7)
+		logger.Info("done", slog.Group("db", "host", "localhost"), slog.Group("empty"))
+		handler.Flush()
+
+		Expect(sender.Batches()[0][0].Fields).To(Equal([]types.Field{
+			{Key: "app", Value: "backup"},
+			{Key: "req.id", Value: "7"},
+			{Key: "req.db.host", Value: "localhost"},
+		}))
+	})
+	It("should map the slog levels to message levels", func() {
+		Expect(logsink.MessageLevel(slog.LevelDebug)).To(Equal(types.Debug))
+		Expect(logsink.MessageLevel(slog.LevelInfo)).To(Equal(types.Info))
+		Expect(logsink.MessageLevel(slog.LevelWarn + 1)).To(Equal(types.Warning))
+		Expect(logsink.MessageLevel(slog.LevelError + 4)).To(Equal(types.Error))
+	})
+})
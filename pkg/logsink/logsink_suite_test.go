@@ -0,0 +1,174 @@
+package logsink_test
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/logsink"
+	"github.com/containrrr/shoutrrr/pkg/router"
+	"github.com/containrrr/shoutrrr/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestLogSink(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Shoutrrr Log Sink Suite")
+}
+
+var _ logsink.Sender = &router.ServiceRouter{}
+
+// recordingSender records the notifications sent using it
+type recordingSender struct {
+	mutex   sync.Mutex
+	batches [][]types.MessageItem
+	params  []types.Params
+	err     error
+}
+
+func (rs *recordingSender) SendItems(items []types.MessageItem, params types.Params) []error {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	rs.batches = append(rs.batches, items)
+	rs.params = append(rs.params, params)
+	return []error{nil, rs.err}
+}
+
+func (rs *recordingSender) Batches() [][]types.MessageItem {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	return rs.batches
+}
+
+// blockingSender records the notifications sent using it, but doesn't return until it is released
+type blockingSender struct {
+	recordingSender
+	started  chan struct{}
+	released chan struct{}
+}
+
+func (bs *blockingSender) SendItems(items []types.MessageItem, params types.Params) []error {
+	bs.started <- struct{}{}
+	<-bs.released
+	return bs.recordingSender.SendItems(items, params)
+}
+
+// texts returns the texts of the items in each batch
+func texts(batches [][]types.MessageItem) [][]string {
+	result := make([][]string, len(batches))
+	for i, batch := range batches {
+		for _, item := range batch {
+			result[i] = append(result[i], item.Text)
+		}
+	}
+	return result
+}
+
+var _ = Describe("the log writer", func() {
+	var sender *recordingSender
+	BeforeEach(func() {
+		sender = &recordingSender{}
+	})
+
+	It("should send every message written by a logger as an item", func() {
+		writer := logsink.NewWriter(sender, types.Error, nil)
+		logger := log.New(writer, "", 0)
+		logger.Print("first")
+		logger.Print("second\nline")
+		writer.Flush()
+
+		Expect(texts(sender.Batches())).To(Equal([][]string{{"first", "second\nline"}}))
+		item := sender.Batches()[0][0]
+		Expect(item.Level).To(Equal(types.Error))
+		Expect(item.Timestamp).To(BeTemporally("~", time.Now(), time.Second))
+	})
+	It("should send the messages once the interval has passed", func() {
+		writer := logsink.NewWriter(sender, types.Info, &logsink.Options{Interval: 10 * time.Millisecond})
+		_, _ = writer.Write([]byte("message\n"))
+
+		Eventually(sender.Batches).Should(HaveLen(1))
+	})
+	It("should send the messages right away once the maximum number of items is reached", func() {
+		writer := logsink.NewWriter(sender, types.Info, &logsink.Options{Interval: time.Hour, MaxItems: 2})
+		for _, message := range []string{"a", "b", "c"} {
+			_, _ = writer.Write([]byte(message))
+		}
+
+		Eventually(sender.Batches).Should(HaveLen(1))
+		writer.Flush()
+		Expect(texts(sender.Batches())).To(Equal([][]string{{"a", "b"}, {"c"}}))
+	})
+	It("should pass a copy of the params with every notification", func() {
+		params := types.Params{"title": "Logs"}
+		writer := logsink.NewWriter(sender, types.Info, &logsink.Options{Params: params, MaxItems: 1})
+		_, _ = writer.Write([]byte("a"))
+		_, _ = writer.Write([]byte("b"))
+		writer.Flush()
+
+		Expect(sender.params).To(Equal([]types.Params{params, params}))
+		sender.params[0]["title"] = "changed"
+		Expect(params["title"]).To(Equal("Logs"))
+	})
+	It("should pass the errors of failed notifications to the error handler", func() {
+		sender.err = errors.New("unavailable")
+		var handled []error
+		writer := logsink.NewWriter(sender, types.Info, &logsink.Options{ErrorHandler: func(errs []error) {
+			handled = errs
+		}})
+		_, _ = writer.Write([]byte("message"))
+		writer.Flush()
+
+		Expect(handled).To(Equal([]error{sender.err}))
+	})
+	It("should drop the messages beyond the queue limit, and send a summary of them", func() {
+		sender := &blockingSender{started: make(chan struct{}), released: make(chan struct{})}
+		writer := logsink.NewWriter(sender, types.Info, &logsink.Options{MaxItems: 1, MaxQueued: 1})
+		_, _ = writer.Write([]byte("a"))
+		<-sender.started
+		for _, message := range []string{"b", "c", "d"} {
+			_, _ = writer.Write([]byte(message))
+		}
+
+		go func() {
+			for range sender.started {
+				sender.released <- struct{}{}
+			}
+		}()
+		sender.released <- struct{}{}
+		writer.Flush()
+		close(sender.started)
+
+		batches := sender.Batches()
+		Expect(texts(batches[:2])).To(Equal([][]string{{"a"}, {"b"}}))
+		Expect(batches).To(HaveLen(3))
+		Expect(batches[2]).To(HaveLen(1))
+		Expect(batches[2][0].Text).To(HavePrefix("2 items were dropped"))
+		Expect(batches[2][0].Level).To(Equal(types.Warning))
+	})
+	It("should wait for the messages added while flushing", func() {
+		writer := logsink.NewWriter(sender, types.Info, &logsink.Options{MaxItems: 1})
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = writer.Write([]byte("message"))
+				writer.Flush()
+			}()
+		}
+		wg.Wait()
+
+		Expect(sender.Batches()).To(HaveLen(10))
+	})
+	It("should not send anything when flushed without any messages", func() {
+		writer := logsink.NewWriter(sender, types.Info, nil)
+		_, _ = writer.Write([]byte("\n"))
+		writer.Flush()
+
+		Expect(sender.Batches()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,42 @@
+package logsink
+
+import (
+	"strings"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// Writer is an io.Writer that sends the messages written to it as message items of a fixed level. It is meant to be
+// used as the output of a log.Logger, which writes every message using a single call.
+type Writer struct {
+	batcher *Batcher
+	level   types.MessageLevel
+}
+
+// NewWriter returns a Writer sending the messages written to it using sender, as items with the given level
+func NewWriter(sender Sender, level types.MessageLevel, options *Options) *Writer {
+	return &Writer{
+		batcher: NewBatcher(sender, options),
+		level:   level,
+	}
+}
+
+// Write adds the message to the current batch, without the trailing newline. It never returns an error, since the
+// message is sent in the background.
+func (w *Writer) Write(p []byte) (int, error) {
+	text := strings.TrimSuffix(string(p), "\n")
+	if text != "" {
+		w.batcher.Add(types.MessageItem{
+			Text:      text,
+			Timestamp: time.Now(),
+			Level:     w.level,
+		})
+	}
+	return len(p), nil
+}
+
+// Flush sends the messages that have not been sent yet, and waits for every notification to be sent
+func (w *Writer) Flush() {
+	w.batcher.Flush()
+}
@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/containrrr/shoutrrr/pkg/router"
+)
+
+// Namespace is the prefix of the names of all metrics
+const Namespace = "shoutrrr"
+
+// Collector records metrics about the notifications sent using the routers it has been added to as an observer.
+// It is a prometheus.Collector, that can be registered with any prometheus.Registerer.
+type Collector struct {
+	sends      *prometheus.CounterVec
+	successes  *prometheus.CounterVec
+	failures   *prometheus.CounterVec
+	duration   *prometheus.HistogramVec
+	attempts   *prometheus.CounterVec
+	retries    *prometheus.CounterVec
+	timeouts   *prometheus.CounterVec
+	skipped    *prometheus.CounterVec
+	suppressed *prometheus.CounterVec
+}
+
+var _ router.Observer = &Collector{}
+var _ prometheus.Collector = &Collector{}
+
+// NewCollector returns a new Collector, without any recorded metrics
+func NewCollector() *Collector {
+	labels := []string{"scheme"}
+	counter := func(name string, help string) *prometheus.CounterVec {
+		return prometheus.NewCounterVec(prometheus.CounterOpts{Namespace: Namespace, Name: name, Help: help}, labels)
+	}
+
+	return &Collector{
+		sends:     counter("sends_total", "Number of notifications sent using a service, including the fallbacks of failover groups that were tried"),
+		successes: counter("send_successes_total", "Number of notifications successfully sent using a service"),
+		failures:  counter("send_failures_total", "Number of notifications that could not be sent using a service"),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "send_duration_seconds",
+			Help:      "Time spent sending a notification using a service, including retries",
+			Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+		}, labels),
+		attempts:   counter("send_attempts_total", "Number of attempts made to send a notification using a service"),
+		retries:    counter("send_retries_total", "Number of times a failed notification was retried using a service"),
+		timeouts:   counter("send_timeouts_total", "Number of attempts that failed because they exceeded the timeout"),
+		skipped:    counter("sends_skipped_total", "Number of notifications not sent to a service because of its level rules"),
+		suppressed: counter("sends_suppressed_total", "Number of notifications not sent to a service because they were duplicates"),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, collector := range c.collectors() {
+		collector.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, collector := range c.collectors() {
+		collector.Collect(ch)
+	}
+}
+
+func (c *Collector) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		c.sends, c.successes, c.failures, c.duration, c.attempts, c.retries, c.timeouts, c.skipped, c.suppressed,
+	}
+}
+
+// NotificationStarted implements router.Observer
+func (c *Collector) NotificationStarted(ctx context.Context, _ router.NotificationInfo) context.Context {
+	return ctx
+}
+
+// NotificationFinished implements router.Observer, recording the services that were skipped or suppressed
+func (c *Collector) NotificationFinished(_ context.Context, _ router.NotificationInfo, results []router.Result) {
+	for _, result := range results {
+		if result.Skipped {
+			c.skipped.WithLabelValues(result.Scheme).Inc()
+		} else if result.Suppressed {
+			c.suppressed.WithLabelValues(result.Scheme).Inc()
+		}
+	}
+}
+
+// ServiceStarted implements router.Observer
+func (c *Collector) ServiceStarted(ctx context.Context, _ router.ServiceInfo) context.Context {
+	return ctx
+}
+
+// AttemptFinished implements router.Observer, recording the attempts and timeouts
+func (c *Collector) AttemptFinished(_ context.Context, info router.ServiceInfo, _ int, err error) {
+	c.attempts.WithLabelValues(info.Scheme).Inc()
+	if router.IsTimeout(err) {
+		c.timeouts.WithLabelValues(info.Scheme).Inc()
+	}
+}
+
+// ServiceFinished implements router.Observer, recording the outcome and duration of sending the notification
+func (c *Collector) ServiceFinished(_ context.Context, info router.ServiceInfo, result router.Result) {
+	c.sends.WithLabelValues(info.Scheme).Inc()
+	if result.Err == nil {
+		c.successes.WithLabelValues(info.Scheme).Inc()
+	} else {
+		c.failures.WithLabelValues(info.Scheme).Inc()
+	}
+	if result.Attempts > 1 {
+		c.retries.WithLabelValues(info.Scheme).Add(float64(result.Attempts - 1))
+	}
+	c.duration.WithLabelValues(info.Scheme).Observe(result.Duration.Seconds())
+}
@@ -0,0 +1,116 @@
+package metrics_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/containrrr/shoutrrr/pkg/metrics"
+	"github.com/containrrr/shoutrrr/pkg/router"
+	"github.com/containrrr/shoutrrr/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestMetrics(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Shoutrrr Metrics Suite")
+}
+
+var _ = Describe("the metrics collector", func() {
+	var collector *metrics.Collector
+	var client *http.Client
+
+	newRouter := func(urls ...string) *router.ServiceRouter {
+		sr, err := router.New(nil, urls...)
+		Expect(err).NotTo(HaveOccurred())
+		sr.SetHTTPClient(client)
+		sr.AddObserver(collector)
+		return sr
+	}
+
+	BeforeEach(func() {
+		collector = metrics.NewCollector()
+		client = &http.Client{Transport: &http.Transport{}}
+		httpmock.ActivateNonDefault(client)
+		httpmock.RegisterResponder("POST", "https://ok.example.com/", httpmock.NewStringResponder(200, ""))
+		httpmock.RegisterResponder("POST", "https://down.example.com/", httpmock.NewStringResponder(503, ""))
+		httpmock.RegisterResponder("POST", "https://slow.example.com/", func(req *http.Request) (*http.Response, error) {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		})
+	})
+	AfterEach(func() {
+		httpmock.DeactivateAndReset()
+	})
+
+	It("should be registrable", func() {
+		Expect(prometheus.NewPedanticRegistry().Register(collector)).To(Succeed())
+	})
+	It("should count the successful and failed notifications", func() {
+		sr := newRouter("generic://ok.example.com/", "generic://down.example.com/?shoutrrr_retries=2&shoutrrr_backoff=1ms")
+		sr.Send("message", nil)
+		sr.Send("message", nil)
+
+		Expect(testutil.ToFloat64(metric(collector, "shoutrrr_send_successes_total"))).To(Equal(2.0))
+		Expect(testutil.ToFloat64(metric(collector, "shoutrrr_send_failures_total"))).To(Equal(2.0))
+		Expect(testutil.ToFloat64(metric(collector, "shoutrrr_sends_total"))).To(Equal(4.0))
+		Expect(testutil.ToFloat64(metric(collector, "shoutrrr_send_retries_total"))).To(Equal(4.0))
+		Expect(testutil.ToFloat64(metric(collector, "shoutrrr_send_attempts_total"))).To(Equal(8.0))
+		Expect(testutil.CollectAndCount(collector, "shoutrrr_send_duration_seconds")).To(Equal(1))
+	})
+	It("should count the timeouts", func() {
+		sr := newRouter("generic://slow.example.com/")
+		sr.Timeout = 10 * time.Millisecond
+		sr.Send("message", nil)
+
+		Expect(testutil.ToFloat64(metric(collector, "shoutrrr_send_timeouts_total"))).To(Equal(1.0))
+	})
+	It("should count the skipped notifications", func() {
+		sr := newRouter("generic://ok.example.com/?shoutrrr_minlevel=error")
+		sr.SendItems([]types.MessageItem{{Text: "debug", Level: types.Debug}}, nil)
+
+		Expect(testutil.ToFloat64(metric(collector, "shoutrrr_sends_skipped_total"))).To(Equal(1.0))
+		Expect(testutil.CollectAndCount(collector, "shoutrrr_sends_total")).To(Equal(0))
+	})
+	It("should label the metrics using the service scheme", func() {
+		sr := newRouter("generic://ok.example.com/")
+		sr.Send("message", nil)
+
+		expected := `
+# HELP shoutrrr_send_successes_total Number of notifications successfully sent using a service
+# TYPE shoutrrr_send_successes_total counter
+shoutrrr_send_successes_total{scheme="generic"} 1
+`
+		Expect(testutil.CollectAndCompare(collector, strings.NewReader(expected), "shoutrrr_send_successes_total")).To(Succeed())
+	})
+})
+
+// metric returns a collector that only collects the named metric
+func metric(collector prometheus.Collector, name string) prometheus.Collector {
+	return &filteredCollector{collector, name}
+}
+
+type filteredCollector struct {
+	prometheus.Collector
+	name string
+}
+
+func (fc *filteredCollector) Collect(ch chan<- prometheus.Metric) {
+	all := make(chan prometheus.Metric)
+	go func() {
+		fc.Collector.Collect(all)
+		close(all)
+	}()
+	for metric := range all {
+		if strings.Contains(metric.Desc().String(), `"`+fc.name+`"`) {
+			ch <- metric
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package router
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// DedupeKeyParam is the param used to set the key that identifies duplicate notifications. If it is not set,
+// notifications with identical content are considered duplicates. The param is not passed on to the services.
+const DedupeKeyParam = "dedupekey"
+
+// deduplicator suppresses duplicate notifications sent using a service within a window. It is shared by all copies
+// of the routed service.
+type deduplicator struct {
+	window  time.Duration
+	summary bool
+
+	mutex sync.Mutex
+	seen  map[string]*duplicates
+}
+
+// duplicates is the first notification sent with a dedupe key, and the number of times it was repeated since
+type duplicates struct {
+	first    notification
+	repeated int
+	expiry   *time.Timer
+}
+
+func newDeduplicator(window time.Duration, summary bool) *deduplicator {
+	if window <= 0 {
+		return nil
+	}
+	return &deduplicator{
+		window:  window,
+		summary: summary,
+		seen:    make(map[string]*duplicates),
+	}
+}
+
+// suppress returns whether the notification is a duplicate of one sent within the window. When the window of a
+// notification that has been repeated closes, summarize is called with it, if the summary is enabled.
+func (dd *deduplicator) suppress(n notification, summarize func(summary notification)) bool {
+	if dd == nil {
+		return false
+	}
+
+	key := n.dedupeKey()
+
+	dd.mutex.Lock()
+	defer dd.mutex.Unlock()
+
+	if entry, found := dd.seen[key]; found {
+		entry.repeated++
+		return true
+	}
+
+	entry := &duplicates{first: n}
+	dd.seen[key] = entry
+
+	entry.expiry = time.AfterFunc(dd.window, func() {
+		dd.mutex.Lock()
+		delete(dd.seen, key)
+		repeated := entry.repeated
+		dd.mutex.Unlock()
+
+		if repeated > 0 && dd.summary {
+			summarize(entry.first.repeatSummary(repeated))
+		}
+	})
+
+	return false
+}
+
+// forget removes the notification from the sent notifications after it failed to be sent, so that the next
+// duplicate is sent instead of being suppressed
+func (dd *deduplicator) forget(n notification) {
+	if dd == nil {
+		return
+	}
+
+	dd.mutex.Lock()
+	defer dd.mutex.Unlock()
+
+	key := n.dedupeKey()
+	if entry, found := dd.seen[key]; found {
+		entry.expiry.Stop()
+		delete(dd.seen, key)
+	}
+}
+
+// withoutDedupeKey returns the notification with the dedupe key moved from the params, so that it is not passed on
+// to the services
+func (n notification) withoutDedupeKey() notification {
+	key, found := n.params[DedupeKeyParam]
+	if !found {
+		return n
+	}
+
+	params := make(t.Params, len(n.params)-1)
+	for k, v := range n.params {
+		if k != DedupeKeyParam {
+			params[k] = v
+		}
+	}
+
+	n.params = params
+	n.key = key
+	return n
+}
+
+// dedupeKey returns the key identifying duplicates of the notification
+func (n notification) dedupeKey() string {
+	if n.key != "" {
+		return "key:" + n.key
+	}
+
+	content := n.message
+	if n.items != nil {
+		content = t.ItemsToPlain(n.items)
+	}
+	sum := sha256.Sum256([]byte(content))
+	return "content:" + hex.EncodeToString(sum[:])
+}
+
+// repeatSummary returns the notification with a note about how many times it was repeated
+func (n notification) repeatSummary(repeated int) notification {
+	note := fmt.Sprintf("(repeated %d times)", repeated)
+
+	if n.items == nil {
+		n.message = n.message + "\n" + note
+		return n
+	}
+
+	items := append([]t.MessageItem{}, n.items...)
+	n.items = append(items, t.MessageItem{
+		Text:  note,
+		Level: items[len(items)-1].Level,
+	})
+	return n
+}
@@ -0,0 +1,70 @@
+package router
+
+import (
+	"context"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// notification is the content sent to the services, either a plain message or message items
+type notification struct {
+	message string
+	items   []t.MessageItem
+	params  t.Params
+	// key is the dedupe key, taken from the params
+	key string
+	// replay is whether the notification is replayed from the outbox, which bypasses the dedupe
+	replay bool
+}
+
+// forService returns the notification as it should be sent to the service, after applying its level rules.
+// Returns false if there is nothing to send to the service.
+func (n notification) forService(service routedService) (notification, bool) {
+	if n.items == nil {
+		return n, true
+	}
+
+	items := service.props.levels.filter(n.items)
+	if len(items) < 1 {
+		return n, false
+	}
+
+	return notification{items: items, params: n.params, key: n.key}, true
+}
+
+// size returns the size in bytes of the message, or the combined size of the message items
+func (n notification) size() int {
+	if n.items == nil {
+		return len(n.message)
+	}
+
+	size := 0
+	for _, item := range n.items {
+		size += len(item.Text)
+	}
+	return size
+}
+
+// sendUsing sends the notification using the service, passing the message items on as is if it is a RichSender.
+// The context is only passed on to services implementing ContextSender or ContextRichSender.
+func (n notification) sendUsing(ctx context.Context, service t.Service) error {
+	params := n.params
+	if n.items != nil {
+		if richSender, ok := service.(t.RichSender); ok {
+			if contextSender, ok := service.(t.ContextRichSender); ok {
+				return contextSender.SendItemsContext(ctx, n.items, &params)
+			}
+			return richSender.SendItems(n.items, &params)
+		}
+	}
+
+	message := n.message
+	if n.items != nil {
+		message = t.ItemsToPlain(n.items)
+	}
+
+	if contextSender, ok := service.(t.ContextSender); ok {
+		return contextSender.SendContext(ctx, message, &params)
+	}
+	return service.Send(message, &params)
+}
@@ -0,0 +1,85 @@
+package router
+
+import (
+	"context"
+)
+
+// Observer is notified about the notifications sent using a router, e.g. to record metrics or tracing spans.
+// It is added to the router using AddObserver, and its methods are called concurrently.
+type Observer interface {
+	// NotificationStarted is called when the router starts sending a notification to its services, and returns the
+	// context used for sending it
+	NotificationStarted(ctx context.Context, info NotificationInfo) context.Context
+
+	// NotificationFinished is called with the results, ordered by service index, when the notification has been sent
+	// to all of the services
+	NotificationFinished(ctx context.Context, info NotificationInfo, results []Result)
+
+	// ServiceStarted is called when the router starts sending a notification using a service, including the services
+	// of failover groups, and returns the context used for sending it
+	ServiceStarted(ctx context.Context, info ServiceInfo) context.Context
+
+	// AttemptFinished is called with the error, if any, of every attempt made to send a notification using a service
+	AttemptFinished(ctx context.Context, info ServiceInfo, attempt int, err error)
+
+	// ServiceFinished is called with the result when the router is done sending a notification using a service
+	ServiceFinished(ctx context.Context, info ServiceInfo, result Result)
+}
+
+// NotificationInfo describes a notification sent using a router
+type NotificationInfo struct {
+	// Services is the number of services (or failover groups) the notification is sent to
+	Services int
+	// Size is the size in bytes of the message, or the combined size of the message items
+	Size int
+}
+
+// ServiceInfo describes the service used for sending a notification
+type ServiceInfo struct {
+	// Scheme is the service identifier part of the service URL (e.g. "telegram")
+	Scheme string
+	// URL is the service URL, with any password redacted
+	URL string
+	// Size is the size in bytes of the message, or the combined size of the message items
+	Size int
+}
+
+// AddObserver adds an observer that is notified about the notifications sent using the router
+func (router *ServiceRouter) AddObserver(observer Observer) {
+	router.observers = append(router.observers, observer)
+}
+
+// observers calls the same method on each of the observers
+type observers []Observer
+
+func (obs observers) notificationStarted(ctx context.Context, info NotificationInfo) context.Context {
+	for _, observer := range obs {
+		ctx = observer.NotificationStarted(ctx, info)
+	}
+	return ctx
+}
+
+func (obs observers) notificationFinished(ctx context.Context, info NotificationInfo, results []Result) {
+	for _, observer := range obs {
+		observer.NotificationFinished(ctx, info, results)
+	}
+}
+
+func (obs observers) serviceStarted(ctx context.Context, info ServiceInfo) context.Context {
+	for _, observer := range obs {
+		ctx = observer.ServiceStarted(ctx, info)
+	}
+	return ctx
+}
+
+func (obs observers) attemptFinished(ctx context.Context, info ServiceInfo, attempt int, err error) {
+	for _, observer := range obs {
+		observer.AttemptFinished(ctx, info, attempt, err)
+	}
+}
+
+func (obs observers) serviceFinished(ctx context.Context, info ServiceInfo, result Result) {
+	for _, observer := range obs {
+		observer.ServiceFinished(ctx, info, result)
+	}
+}
@@ -0,0 +1,237 @@
+package router
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// OutboxEntry is a notification that has not yet been delivered to one of the router's services
+type OutboxEntry struct {
+	// ID identifies the entry in the outbox
+	ID int64 `json:"id"`
+	// Service identifies the service URL the notification should be delivered to, without revealing it
+	Service string `json:"service"`
+	// Scheme is the service identifier part of the service URL (e.g. "telegram")
+	Scheme  string   `json:"scheme"`
+	Message string   `json:"message"`
+	Params  t.Params `json:"params,omitempty"`
+	// Created is the time the notification was flushed from the queue
+	Created time.Time `json:"created"`
+	// Attempts is the total number of times the notification has been tried to be sent
+	Attempts int `json:"attempts"`
+	// LastError is the error returned from the last attempt
+	LastError string `json:"lastError,omitempty"`
+}
+
+// outboxJournal is the content of the outbox file
+type outboxJournal struct {
+	NextID  int64         `json:"nextId"`
+	Queued  []string      `json:"queued,omitempty"`
+	Pending []OutboxEntry `json:"pending,omitempty"`
+	Dead    []OutboxEntry `json:"dead,omitempty"`
+}
+
+// Outbox is a file backed journal of queued and undelivered notifications. When set on a ServiceRouter, messages
+// passed to Enqueue are persisted until they have been delivered to every service, and notifications that could not
+// be delivered are replayed on the next Flush or ReplayOutbox.
+// Every change is written to the file immediately, so the same file should not be used by several processes at once.
+type Outbox struct {
+	// TTL is the maximum age of an undelivered notification before it is moved to the dead letters, zero means no limit
+	TTL time.Duration
+	// MaxAttempts is the number of attempts after which an undelivered notification is moved to the dead letters,
+	// zero means no limit
+	MaxAttempts int
+
+	path    string
+	mutex   sync.Mutex
+	journal outboxJournal
+}
+
+// OpenOutbox loads the outbox from the file at path, which is created when the outbox is first changed
+func OpenOutbox(path string) (*Outbox, error) {
+	outbox := &Outbox{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return outbox, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading outbox: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &outbox.journal); err != nil {
+		return nil, fmt.Errorf("error parsing outbox %q: %w", path, err)
+	}
+
+	return outbox, nil
+}
+
+// Queued returns the messages that have been enqueued but not yet flushed
+func (outbox *Outbox) Queued() []string {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+	return append([]string{}, outbox.journal.Queued...)
+}
+
+// Pending returns the notifications that are waiting to be delivered
+func (outbox *Outbox) Pending() []OutboxEntry {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+	return append([]OutboxEntry{}, outbox.journal.Pending...)
+}
+
+// DeadLetters returns the notifications that were given up on, either because they expired or failed permanently
+func (outbox *Outbox) DeadLetters() []OutboxEntry {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+	return append([]OutboxEntry{}, outbox.journal.Dead...)
+}
+
+// ClearDeadLetters removes all dead letters from the outbox
+func (outbox *Outbox) ClearDeadLetters() error {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+	outbox.journal.Dead = nil
+	return outbox.save()
+}
+
+// enqueue adds the message to the queue
+func (outbox *Outbox) enqueue(message string) error {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+	outbox.journal.Queued = append(outbox.journal.Queued, message)
+	return outbox.save()
+}
+
+// schedule removes the messages from the queue and adds them, followed by any unsaved messages, as a single combined
+// notification for each service
+func (outbox *Outbox) schedule(services []routedService, unsaved []string, params t.Params, now time.Time) error {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+
+	queued := append(outbox.journal.Queued, unsaved...)
+	if len(queued) < 1 {
+		return nil
+	}
+
+	message := strings.Join(queued, "\n")
+	for _, service := range services {
+		outbox.journal.NextID++
+		outbox.journal.Pending = append(outbox.journal.Pending, OutboxEntry{
+			ID:      outbox.journal.NextID,
+			Service: service.key,
+			Scheme:  service.scheme,
+			Message: message,
+			Params:  params,
+			Created: now,
+		})
+	}
+	outbox.journal.Queued = nil
+
+	return outbox.save()
+}
+
+// expire moves all pending notifications older than the TTL to the dead letters
+func (outbox *Outbox) expire(now time.Time) error {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+
+	if outbox.TTL <= 0 {
+		return nil
+	}
+
+	pending := outbox.journal.Pending[:0]
+	expired := false
+	for _, entry := range outbox.journal.Pending {
+		if now.Sub(entry.Created) > outbox.TTL {
+			outbox.journal.Dead = append(outbox.journal.Dead, entry)
+			expired = true
+		} else {
+			pending = append(pending, entry)
+		}
+	}
+	outbox.journal.Pending = pending
+
+	if !expired {
+		return nil
+	}
+	return outbox.save()
+}
+
+// complete updates the pending notification with id using the result of trying to deliver it. Delivered
+// notifications are removed, and notifications that failed permanently or ran out of attempts are moved to the dead
+// letters, in which case true is returned.
+func (outbox *Outbox) complete(id int64, result Result, permanent bool) (bool, error) {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+
+	for i, entry := range outbox.journal.Pending {
+		if entry.ID != id {
+			continue
+		}
+
+		if result.Err == nil {
+			outbox.journal.Pending = append(outbox.journal.Pending[:i], outbox.journal.Pending[i+1:]...)
+			return false, outbox.save()
+		}
+
+		entry.Attempts += result.Attempts
+		entry.LastError = result.Err.Error()
+		dead := permanent || (outbox.MaxAttempts > 0 && entry.Attempts >= outbox.MaxAttempts)
+		if dead {
+			outbox.journal.Pending = append(outbox.journal.Pending[:i], outbox.journal.Pending[i+1:]...)
+			outbox.journal.Dead = append(outbox.journal.Dead, entry)
+		} else {
+			outbox.journal.Pending[i] = entry
+		}
+		return dead, outbox.save()
+	}
+
+	return false, nil
+}
+
+// save writes the journal to a temporary file, which then replaces the outbox file, so that a crash while
+// writing does not corrupt the outbox
+func (outbox *Outbox) save() error {
+	data, err := json.Marshal(&outbox.journal)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(outbox.path), filepath.Base(outbox.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error writing outbox: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err = file.Write(data); err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), outbox.path)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing outbox: %w", err)
+	}
+
+	return nil
+}
+
+// serviceKey returns a stable identifier for the service URL, that does not reveal any of its secrets
+func serviceKey(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,180 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// rateLimit is the number of requests allowed per period
+type rateLimit struct {
+	count  int
+	period time.Duration
+}
+
+// parseRateLimit parses a rate limit in the format "<count>/<period>", where the period is either a unit like
+// "sec", "min" or "hour", or a duration like "30s"
+func parseRateLimit(value string) (rateLimit, error) {
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 {
+		return rateLimit{}, fmt.Errorf("invalid rate limit %q, expected format is count/period, e.g. 20/min", value)
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || count < 1 {
+		return rateLimit{}, fmt.Errorf("invalid rate limit count %q", parts[0])
+	}
+
+	period := strings.ToLower(strings.TrimSpace(parts[1]))
+	switch period {
+	case "s", "sec", "second":
+		return rateLimit{count, time.Second}, nil
+	case "m", "min", "minute":
+		return rateLimit{count, time.Minute}, nil
+	case "h", "hour":
+		return rateLimit{count, time.Hour}, nil
+	}
+
+	duration, err := time.ParseDuration(period)
+	if err != nil || duration <= 0 {
+		return rateLimit{}, fmt.Errorf("invalid rate limit period %q", parts[1])
+	}
+	return rateLimit{count, duration}, nil
+}
+
+// serviceLimiter limits the rate of requests made using a service, and optionally coalesces the messages sent
+// within a window into a single notification. It is shared by all copies of the routed service.
+type serviceLimiter struct {
+	limit  rateLimit
+	window time.Duration
+
+	mutex   sync.Mutex
+	tokens  float64
+	last    time.Time
+	batches map[string]*coalescedBatch
+}
+
+// coalescedBatch is the set of messages with equal params that are sent together when the coalescing window closes
+type coalescedBatch struct {
+	messages []string
+	params   t.Params
+	done     chan struct{}
+	result   Result
+}
+
+func newServiceLimiter(limit rateLimit, window time.Duration) *serviceLimiter {
+	if limit.count < 1 && window <= 0 {
+		return nil
+	}
+	return &serviceLimiter{
+		limit:   limit,
+		window:  window,
+		tokens:  float64(limit.count),
+		batches: make(map[string]*coalescedBatch),
+	}
+}
+
+// coalescing returns whether the messages should be coalesced
+func (limiter *serviceLimiter) coalescing() bool {
+	return limiter != nil && limiter.window > 0
+}
+
+// wait blocks until a request can be made without exceeding the rate limit, or until ctx is done
+func (limiter *serviceLimiter) wait(ctx context.Context) error {
+	if limiter == nil || limiter.limit.count < 1 {
+		return nil
+	}
+
+	for {
+		delay := limiter.reserve(time.Now())
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// reserve takes a token from the bucket if there is one, otherwise it returns the time until the next token is added
+func (limiter *serviceLimiter) reserve(now time.Time) time.Duration {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	capacity := float64(limiter.limit.count)
+	interval := limiter.limit.period / time.Duration(limiter.limit.count)
+
+	if !limiter.last.IsZero() {
+		limiter.tokens += float64(now.Sub(limiter.last)) / float64(interval)
+		if limiter.tokens > capacity {
+			limiter.tokens = capacity
+		}
+	}
+	limiter.last = now
+
+	if limiter.tokens >= 1 {
+		limiter.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - limiter.tokens) * float64(interval))
+}
+
+// add adds the message to the current batch of messages with the same params, starting a new one if needed, so that
+// the params of every message are used when it is sent. When a new batch is started, flush is called with it after
+// the coalescing window has passed.
+func (limiter *serviceLimiter) add(n notification, flush func(batch *coalescedBatch)) *coalescedBatch {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	key := paramsKey(n.params)
+	batch, found := limiter.batches[key]
+	if !found {
+		batch = &coalescedBatch{
+			params: n.params,
+			done:   make(chan struct{}),
+		}
+		limiter.batches[key] = batch
+
+		time.AfterFunc(limiter.window, func() {
+			limiter.mutex.Lock()
+			delete(limiter.batches, key)
+			limiter.mutex.Unlock()
+
+			flush(batch)
+			close(batch.done)
+		})
+	}
+
+	batch.messages = append(batch.messages, n.message)
+	return batch
+}
+
+// paramsKey returns a string identifying the params, that is equal for params with the same keys and values
+func paramsKey(params t.Params) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sb := strings.Builder{}
+	for _, key := range keys {
+		sb.WriteString(strconv.Quote(key))
+		sb.WriteByte('=')
+		sb.WriteString(strconv.Quote(params[key]))
+		sb.WriteByte('&')
+	}
+	return sb.String()
+}
@@ -0,0 +1,179 @@
+package router
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/containrrr/shoutrrr/pkg/format"
+	t "github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// RedactURL returns the service URL with its secrets, like passwords and tokens, replaced. The secrets are found
+// using the field meta data of the service config. The URLs of a failover group are redacted individually, and any
+// secret references are left as they are, without resolving them.
+func RedactURL(rawURL string) string {
+	rawURLs := strings.Split(rawURL, FailoverSeparator)
+	for i, serviceURL := range rawURLs {
+		rawURLs[i] = redactServiceURL(strings.TrimSpace(serviceURL))
+	}
+	return strings.Join(rawURLs, FailoverSeparator)
+}
+
+// redactServiceURL redacts a service URL using the config of the service, without initializing the service, since
+// that could make requests, like the login of the matrix service
+func redactServiceURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return redactRawURL(rawURL)
+	}
+
+	configURL := *parsed
+	scheme := strings.Split(parsed.Scheme, "+")[0]
+	var secrets []string
+	if props, err := extractServiceProps(&configURL, forwardsQuery(scheme)); err == nil && props.proxy != nil {
+		if password, ok := props.proxy.User.Password(); ok {
+			secrets = append(secrets, password)
+		}
+	}
+
+	service, err := newService(scheme)
+	if err != nil || scheme != parsed.Scheme || !redactsByConfig(service) {
+		return format.RedactSecrets(redactURLParts(parsed), secrets)
+	}
+
+	// The config is only used for finding the secrets, so it is not the one of the service
+	config := format.GetServiceConfig(service)
+	if err := config.SetURL(&configURL); err != nil {
+		return format.RedactSecrets(redactURLParts(parsed), secrets)
+	}
+	secrets = append(secrets, format.SecretValues(config)...)
+	return format.RedactSecrets(format.RedactURL(config, parsed), secrets)
+}
+
+// redactService sets the URL of the service, with the secrets replaced, and the secrets used for redacting its errors.
+// The URL is redacted using the config of the service if it has one, and the URL is not a custom URL. Otherwise every
+// part of the URL that could contain a secret is replaced.
+func (routed *routedService) redactService(rawURL string, custom bool) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		routed.url = redactRawURL(rawURL)
+		return
+	}
+
+	if password, ok := parsed.User.Password(); ok {
+		routed.secrets = append(routed.secrets, password)
+	}
+	if proxy := routed.props.proxy; proxy != nil {
+		if password, ok := proxy.User.Password(); ok {
+			routed.secrets = append(routed.secrets, password)
+		}
+	}
+
+	if !format.HasServiceConfig(routed.Service) {
+		routed.url = format.RedactSecrets(redactURLParts(parsed), routed.secrets)
+		return
+	}
+
+	config := format.GetServiceConfig(routed.Service)
+	routed.secrets = append(routed.secrets, format.SecretValues(config)...)
+	if custom || !redactsByConfig(routed.Service) {
+		routed.url = format.RedactSecrets(redactURLParts(parsed), routed.secrets)
+	} else {
+		routed.url = format.RedactSecrets(format.RedactURL(config, parsed), routed.secrets)
+	}
+}
+
+// redactsByConfig returns whether the secrets of the service URLs can be found using the field meta data of the
+// service config. This is not the case for services that pass the URL on as a webhook URL, like generic, if their
+// config has no secret fields.
+func redactsByConfig(service t.Service) bool {
+	if !format.HasServiceConfig(service) {
+		return false
+	}
+	if _, forwardsURL := service.(t.CustomURLService); !forwardsURL {
+		return true
+	}
+	return format.HasSecretFields(format.GetServiceConfig(service))
+}
+
+// redactURLParts returns the URL with the user info, the path segments and the query values replaced, for the URLs
+// that can not be redacted using the field meta data of a service config. Only the scheme, host and query keys are
+// kept, since tokens can be in any of the other parts, like the user of telegram URLs or the path of webhook URLs.
+func redactURLParts(serviceURL *url.URL) string {
+	redacted := *serviceURL
+	if redacted.User != nil {
+		if _, hasPassword := redacted.User.Password(); hasPassword {
+			redacted.User = url.UserPassword(format.RedactedValue, format.RedactedValue)
+		} else {
+			redacted.User = url.User(format.RedactedValue)
+		}
+	}
+
+	if redacted.Path != "" && redacted.Path != "/" {
+		segments := strings.Split(redacted.Path, "/")
+		for i, segment := range segments {
+			if segment != "" {
+				segments[i] = format.RedactedValue
+			}
+		}
+		redacted.Path = strings.Join(segments, "/")
+		redacted.RawPath = ""
+	}
+	redacted.Opaque = ""
+
+	if redacted.RawQuery != "" {
+		query := redacted.Query()
+		for key, values := range query {
+			for i := range values {
+				values[i] = format.RedactedValue
+			}
+			query[key] = values
+		}
+		redacted.RawQuery = query.Encode()
+	}
+	redacted.Fragment = ""
+	redacted.RawFragment = ""
+
+	return redacted.String()
+}
+
+// redactRawURL redacts the user info of a URL that could not be parsed
+func redactRawURL(rawURL string) string {
+	schemeEnd := strings.Index(rawURL, "://")
+	userEnd := strings.LastIndex(rawURL, "@")
+	if schemeEnd < 0 || userEnd < schemeEnd {
+		return rawURL
+	}
+	return rawURL[:schemeEnd+3] + format.RedactedValue + rawURL[userEnd:]
+}
+
+// redactedError hides secrets in the message of an error, while keeping the original error available to errors.As
+type redactedError struct {
+	err     error
+	message string
+}
+
+func (e redactedError) Error() string { return e.message }
+func (e redactedError) Unwrap() error { return e.err }
+
+// redactError returns err with the secrets replaced in its message
+func redactError(err error, secrets []string) error {
+	if err == nil || len(secrets) == 0 {
+		return err
+	}
+	message := format.RedactSecrets(err.Error(), secrets)
+	if message == err.Error() {
+		return err
+	}
+	return redactedError{err: err, message: message}
+}
+
+// redactURLError redacts the URL of errors returned when parsing it
+func redactURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactRawURL(urlErr.URL)
+	}
+	return err
+}
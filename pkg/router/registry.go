@@ -0,0 +1,91 @@
+package router
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// ServiceFactory returns a new uninitialized service instance
+type ServiceFactory func() t.Service
+
+// serviceMapMutex guards serviceMap, which is the registry of all available services
+var serviceMapMutex sync.RWMutex
+
+// schemePattern matches valid service schemes. The plus sign is reserved for custom URLs (e.g. "teams+https")
+var schemePattern = regexp.MustCompile(`^[a-z][a-z0-9.-]*$`)
+
+// RegisterService adds the service created by factory to the registry, using scheme as the service identifier in
+// service URLs. An error is returned if the scheme is invalid or if a service has already been registered for it.
+// Registered services are available to all routers, and are included in ListServices.
+func RegisterService(scheme string, factory ServiceFactory) error {
+	return registerService(scheme, factory, false)
+}
+
+// OverrideService adds the service created by factory to the registry like RegisterService, replacing any service
+// that has already been registered for the scheme, including the built-in ones
+func OverrideService(scheme string, factory ServiceFactory) error {
+	return registerService(scheme, factory, true)
+}
+
+// UnregisterService removes the service with the scheme from the registry, returning whether it was registered.
+// Routers that already use the service are not affected.
+func UnregisterService(scheme string) bool {
+	serviceMapMutex.Lock()
+	defer serviceMapMutex.Unlock()
+
+	scheme = strings.ToLower(scheme)
+	_, found := serviceMap[scheme]
+	delete(serviceMap, scheme)
+	return found
+}
+
+// RegisteredServices returns the schemes of all registered services in alphabetical order
+func RegisteredServices() []string {
+	serviceMapMutex.RLock()
+	defer serviceMapMutex.RUnlock()
+
+	services := make([]string, 0, len(serviceMap))
+	for scheme := range serviceMap {
+		services = append(services, scheme)
+	}
+	sort.Strings(services)
+
+	return services
+}
+
+func registerService(scheme string, factory ServiceFactory, override bool) error {
+	scheme = strings.ToLower(scheme)
+	if !schemePattern.MatchString(scheme) {
+		return fmt.Errorf("invalid service scheme %q", scheme)
+	}
+	if factory == nil {
+		return fmt.Errorf("no factory supplied for service %q", scheme)
+	}
+
+	serviceMapMutex.Lock()
+	defer serviceMapMutex.Unlock()
+
+	if _, found := serviceMap[scheme]; found && !override {
+		return fmt.Errorf("service %q is already registered", scheme)
+	}
+	serviceMap[scheme] = factory
+
+	return nil
+}
+
+// newService returns a new uninitialized service instance
+func newService(serviceScheme string) (t.Service, error) {
+	serviceMapMutex.RLock()
+	serviceFactory, valid := serviceMap[strings.ToLower(serviceScheme)]
+	serviceMapMutex.RUnlock()
+
+	if !valid {
+		return nil, fmt.Errorf("unknown service %q", serviceScheme)
+	}
+	return serviceFactory(), nil
+}
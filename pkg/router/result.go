@@ -0,0 +1,57 @@
+package router
+
+import (
+	"fmt"
+	"time"
+)
+
+// Result is the outcome of sending a notification using one of the router's services
+type Result struct {
+	// Index is the position of the service in the router, in the order they were added
+	Index int
+	// Scheme is the service identifier part of the service URL (e.g. "telegram")
+	Scheme string
+	// URL is the service URL with any secrets redacted
+	URL string
+	// Duration is the total time spent sending the notification
+	Duration time.Duration
+	// Attempts is the number of times the notification was tried to be sent
+	Attempts int
+	// Err is the error returned from the last attempt, or nil if the notification was sent successfully
+	Err error
+	// Skipped is set if nothing was sent using the service, since none of the message items matched its level rules
+	Skipped bool
+	// Suppressed is set if nothing was sent using the service, since the notification was a duplicate of one sent
+	// within the dedupe window
+	Suppressed bool
+	// Failures are the results of the services in the failover group that failed before this one was used
+	Failures []Result
+}
+
+// Failed returns whether the notification could not be sent
+func (r Result) Failed() bool {
+	return r.Err != nil
+}
+
+// String returns a short summary of the result, e.g. "telegram OK" or "smtp failed: auth"
+func (r Result) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("%s failed: %v", r.Scheme, r.Err)
+	}
+	if r.Skipped {
+		return fmt.Sprintf("%s skipped", r.Scheme)
+	}
+	if r.Suppressed {
+		return fmt.Sprintf("%s suppressed duplicate", r.Scheme)
+	}
+	return fmt.Sprintf("%s OK", r.Scheme)
+}
+
+// ResultErrors returns the errors of the results, with nil for every successful result
+func ResultErrors(results []Result) []error {
+	errs := make([]error, len(results))
+	for i, result := range results {
+		errs[i] = result.Err
+	}
+	return errs
+}
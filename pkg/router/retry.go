@@ -0,0 +1,139 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"syscall"
+	"time"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
+)
+
+// RetryPolicy controls how the router retries notifications that failed because of transient errors.
+// A retry sends the whole notification again, so services that split a message into several requests, like discord,
+// can post the parts that were delivered before the failure again.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a notification is tried to be sent, 1 or less disables retries
+	MaxAttempts int
+	// Backoff is the delay before the first retry, doubled for every subsequent retry
+	Backoff time.Duration
+	// MaxBackoff is the upper limit of the delay between retries, zero means no limit
+	MaxBackoff time.Duration
+	// Jitter is the fraction (0-1) of the delay that is randomized, to prevent retries from being synchronized
+	Jitter float64
+	// Retryable classifies errors as retryable or not, IsRetryable is used if nil
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy is the policy used for new routers, which does not retry failed notifications
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 1,
+	Backoff:     time.Second,
+	MaxBackoff:  30 * time.Second,
+	Jitter:      0.2,
+}
+
+// IsRetryable returns whether the error is likely to be caused by a transient condition, like rate limiting,
+// timeouts or dropped connections, and thus might succeed if retried. Errors that will not go away by retrying, like
+// invalid URLs or certificates, and cancellation of the context are never retried.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var retryableErr t.RetryableError
+	if errors.As(err, &retryableErr) {
+		return retryableErr.Retryable()
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary
+	}
+
+	// The connection could not be made, or was closed before a response was received
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// IsPermanent returns whether the error is known to not go away by retrying, like a request that the server rejected
+// with a client error status code other than rate limiting. Errors that can not be classified are not permanent.
+func IsPermanent(err error) bool {
+	var statusErr *util.StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 400 && statusErr.StatusCode < 500 && !statusErr.Retryable()
+	}
+	return false
+}
+
+func (policy *RetryPolicy) retryable(err error) bool {
+	if policy.Retryable != nil {
+		return policy.Retryable(err)
+	}
+	return IsRetryable(err)
+}
+
+// delay returns the duration to wait before the next attempt, after attempt number of attempts failed with err.
+// If the server requested a longer delay than the backoff, that delay is used instead.
+func (policy *RetryPolicy) delay(attempt int, err error) time.Duration {
+	delay := policy.Backoff
+	for i := 1; i < attempt && (policy.MaxBackoff <= 0 || delay < policy.MaxBackoff); i++ {
+		delay *= 2
+	}
+
+	if policy.MaxBackoff > 0 && delay > policy.MaxBackoff {
+		delay = policy.MaxBackoff
+	}
+
+	if policy.Jitter > 0 && delay > 0 {
+		jitter := time.Duration(policy.Jitter * float64(delay))
+		delay -= time.Duration(rand.Int63n(int64(jitter) + 1))
+	}
+
+	var retryableErr t.RetryableError
+	if errors.As(err, &retryableErr) {
+		if retryAfter := retryableErr.RetryAfter(); retryAfter > delay {
+			delay = retryAfter
+		}
+	}
+
+	return delay
+}
+
+// timeoutError is returned when a single attempt of sending a notification took longer than the router timeout
+type timeoutError struct {
+	scheme string
+}
+
+// IsTimeout returns whether the error was caused by an attempt taking longer than the router's Timeout
+func IsTimeout(err error) bool {
+	var te timeoutError
+	return errors.As(err, &te)
+}
+
+func (te timeoutError) Error() string {
+	return "failed to send using " + te.scheme + ": timed out"
+}
+
+// Retryable returns true, since the next attempt might be faster
+func (te timeoutError) Retryable() bool {
+	return true
+}
+
+// RetryAfter returns zero, since no delay has been requested
+func (te timeoutError) RetryAfter() time.Duration {
+	return 0
+}
@@ -1,28 +1,65 @@
 package router
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
-	"reflect"
 	"strings"
 	"time"
 
+	"github.com/containrrr/shoutrrr/pkg/format"
 	t "github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
 )
 
 // ServiceRouter is responsible for routing a message to a specific notification service using the notification URL
 type ServiceRouter struct {
-	logger   t.StdLogger
-	services []t.Service
-	queue    []string
-	Timeout  time.Duration
+	logger      t.StdLogger
+	structured  t.StructuredLogger
+	httpClient  *http.Client
+	services    []routedService
+	queue       []string
+	Timeout     time.Duration
+	RetryPolicy RetryPolicy
+	// Outbox persists queued and undelivered notifications if set, see Outbox
+	Outbox *Outbox
+	// observers are notified about sent notifications, see AddObserver
+	observers observers
 }
 
+// routedService is a service added to the router, together with its router props and the meta data used for
+// reporting results
+type routedService struct {
+	t.Service
+	scheme string
+	url    string
+	key    string
+	props  serviceProps
+	// fallbacks are tried in order when sending using the service fails
+	fallbacks []routedService
+	// limiter limits the rate of requests and coalesces messages, if enabled using the service props
+	limiter *serviceLimiter
+	// dedupe suppresses duplicate notifications, if enabled using the service props
+	dedupe *deduplicator
+	// tlsConfig is the config created from the TLS props, or nil if they are not set
+	tlsConfig *tls.Config
+	// secrets are replaced in the messages of the errors returned by the service
+	secrets []string
+}
+
+// FailoverSeparator separates the URLs of a failover group when given as a single service URL,
+// e.g. "opsgenie://...||smtp://..."
+const FailoverSeparator = "||"
+
 // New creates a new service router using the specified logger and service URLs
 func New(logger t.StdLogger, serviceURLs ...string) (*ServiceRouter, error) {
 	router := ServiceRouter{
-		logger:  logger,
-		Timeout: 10 * time.Second,
+		logger:      logger,
+		Timeout:     10 * time.Second,
+		RetryPolicy: DefaultRetryPolicy,
 	}
 
 	for _, serviceURL := range serviceURLs {
@@ -33,118 +70,539 @@ func New(logger t.StdLogger, serviceURLs ...string) (*ServiceRouter, error) {
 	return &router, nil
 }
 
-// AddService initializes the specified service from its URL, and adds it if no errors occur
+// AddService initializes the specified service from its URL, and adds it if no errors occur.
+// If the URL contains several service URLs separated by FailoverSeparator, they are added as a failover group.
 func (router *ServiceRouter) AddService(serviceURL string) error {
-	service, err := router.initService(serviceURL)
-	if err == nil {
-		router.services = append(router.services, service)
+	if strings.Contains(serviceURL, FailoverSeparator) {
+		return router.AddFailoverGroup(strings.Split(serviceURL, FailoverSeparator)...)
 	}
-	return err
+
+	service, err := router.initRoutedService(serviceURL)
+	if err != nil {
+		return err
+	}
+
+	router.services = append(router.services, *service)
+	return nil
+}
+
+// AddFailoverGroup initializes the specified services from their URLs, and adds them as a single failover group if
+// no errors occur. Notifications are only sent using the first service, and if that fails, using the next one,
+// until one of them succeeds.
+func (router *ServiceRouter) AddFailoverGroup(serviceURLs ...string) error {
+	if len(serviceURLs) < 1 {
+		return fmt.Errorf("failover group must contain at least one service")
+	}
+
+	var group *routedService
+	for _, serviceURL := range serviceURLs {
+		service, err := router.initRoutedService(strings.TrimSpace(serviceURL))
+		if err != nil {
+			return err
+		}
+
+		if group == nil {
+			group = service
+		} else {
+			group.fallbacks = append(group.fallbacks, *service)
+		}
+	}
+
+	router.services = append(router.services, *group)
+	return nil
 }
 
 // Send sends the specified message using the routers underlying services
 func (router *ServiceRouter) Send(message string, params *t.Params) []error {
+	return router.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends the specified message using the routers underlying services,
+// cancelling any pending requests when ctx is done
+func (router *ServiceRouter) SendContext(ctx context.Context, message string, params *t.Params) []error {
 	if router == nil {
 		return []error{fmt.Errorf("error sending message: no senders")}
 	}
 
-	serviceCount := len(router.services)
-	errors := make([]error, serviceCount)
-	results := router.SendAsync(message, params)
+	return ResultErrors(router.SendResults(ctx, message, params))
+}
 
-	for i := range router.services {
-		errors[i] = <-results
+// SendResults sends the specified message using the routers underlying services,
+// returning the results ordered by the services' index in the router
+func (router *ServiceRouter) SendResults(ctx context.Context, message string, params *t.Params) []Result {
+	if router == nil {
+		return []Result{{Err: fmt.Errorf("error sending message: no senders")}}
 	}
 
-	return errors
+	return collectResults(router.SendAsyncResults(ctx, message, params), len(router.services))
 }
 
 // SendItems sends the specified message items using the routers underlying services
 func (router *ServiceRouter) SendItems(items []t.MessageItem, params t.Params) []error {
+	return router.SendItemsContext(context.Background(), items, params)
+}
+
+// SendItemsContext sends the specified message items using the routers underlying services,
+// cancelling any pending requests when ctx is done
+func (router *ServiceRouter) SendItemsContext(ctx context.Context, items []t.MessageItem, params t.Params) []error {
 	if router == nil {
 		return []error{fmt.Errorf("error sending message: no senders")}
 	}
 
-	// Fallback using old API for now
-	message := strings.Builder{}
-	for _, item := range items {
-		message.WriteString(item.Text)
-	}
+	return ResultErrors(router.SendItemsResults(ctx, items, params))
+}
 
-	serviceCount := len(router.services)
-	errors := make([]error, serviceCount)
-	results := router.SendAsync(message.String(), &params)
+// SendItemsResults sends the specified message items using the routers underlying services,
+// returning the results ordered by the services' index in the router
+func (router *ServiceRouter) SendItemsResults(ctx context.Context, items []t.MessageItem, params t.Params) []Result {
+	if router == nil {
+		return []Result{{Err: fmt.Errorf("error sending message: no senders")}}
+	}
 
-	for i := range router.services {
-		errors[i] = <-results
+	if params == nil {
+		params = t.Params{}
+	}
+	if items == nil {
+		items = []t.MessageItem{}
 	}
 
-	return errors
+	return collectResults(router.sendAsync(ctx, notification{items: items, params: params}), len(router.services))
 }
 
 // SendAsync sends the specified message using the routers underlying services
 func (router *ServiceRouter) SendAsync(message string, params *t.Params) chan error {
+	return router.SendAsyncContext(context.Background(), message, params)
+}
+
+// SendAsyncContext sends the specified message using the routers underlying services,
+// cancelling any pending requests when ctx is done
+func (router *ServiceRouter) SendAsyncContext(ctx context.Context, message string, params *t.Params) chan error {
 	serviceCount := len(router.services)
-	proxy := make(chan error, serviceCount)
 	errors := make(chan error, serviceCount)
+	results := router.SendAsyncResults(ctx, message, params)
 
+	go func() {
+		for result := range results {
+			errors <- result.Err
+		}
+		close(errors)
+	}()
+
+	return errors
+}
+
+// SendAsyncResults sends the specified message using the routers underlying services,
+// the results are passed to the returned channel as soon as they are available
+func (router *ServiceRouter) SendAsyncResults(ctx context.Context, message string, params *t.Params) chan Result {
 	if params == nil {
 		params = &t.Params{}
 	}
-	for _, service := range router.services {
-		go sendToService(service, proxy, router.Timeout, message, *params)
+	return router.sendAsync(ctx, notification{message: message, params: *params})
+}
+
+func (router *ServiceRouter) sendAsync(ctx context.Context, n notification) chan Result {
+	serviceCount := len(router.services)
+	proxy := make(chan Result, serviceCount)
+	results := make(chan Result, serviceCount)
+
+	info := NotificationInfo{Services: serviceCount, Size: n.size()}
+	ctx = router.observers.notificationStarted(ctx, info)
+
+	for i, service := range router.services {
+		serviceNotification, ok := n.forService(service)
+		if !ok {
+			proxy <- Result{Index: i, Scheme: service.scheme, URL: service.url, Skipped: true}
+			continue
+		}
+		go router.sendToService(ctx, i, service, proxy, serviceNotification)
 	}
 
 	go func() {
+		ordered := make([]Result, serviceCount)
+		if serviceCount == 0 {
+			router.observers.notificationFinished(ctx, info, ordered)
+		}
 		for i := 0; i < serviceCount; i++ {
-			errors <- <-proxy
+			result := <-proxy
+			ordered[result.Index] = result
+			// Notify the observers before passing on the last result, so that they are done when Send returns
+			if i == serviceCount-1 {
+				router.observers.notificationFinished(ctx, info, ordered)
+			}
+			results <- result
 		}
-		close(errors)
+		close(results)
 	}()
 
-	return errors
+	return results
+}
+
+func collectResults(results chan Result, serviceCount int) []Result {
+	ordered := make([]Result, serviceCount)
+	for result := range results {
+		ordered[result.Index] = result
+	}
+	return ordered
+}
+
+func (router *ServiceRouter) sendToService(ctx context.Context, index int, service routedService, results chan Result, n notification) {
+	start := time.Now()
+	n = n.withoutDedupeKey()
+
+	var result Result
+	if !n.replay && service.dedupe.suppress(n, func(summary notification) { router.sendSummary(service, summary) }) {
+		result = Result{Scheme: service.scheme, URL: service.url, Suppressed: true}
+	} else {
+		if service.limiter.coalescing() && n.items == nil {
+			result = router.sendCoalesced(ctx, service, n)
+		} else {
+			result = router.sendWithFailover(ctx, service, n)
+		}
+		if result.Err != nil && !n.replay {
+			service.dedupe.forget(n)
+		}
+	}
+
+	result.Index = index
+	result.Duration = time.Since(start)
+	results <- result
+}
+
+// sendSummary sends the summary of suppressed duplicates, logging any errors since there is no caller to return them to
+func (router *ServiceRouter) sendSummary(service routedService, summary notification) {
+	result := router.sendWithFailover(context.Background(), service, summary)
+	if result.Err != nil {
+		router.log("Failed to send duplicate summary:", result.Err)
+	}
+}
+
+// sendCoalesced adds the message to the service's current batch, and waits for the batch to be sent
+func (router *ServiceRouter) sendCoalesced(ctx context.Context, service routedService, n notification) Result {
+	batch := service.limiter.add(n, func(batch *coalescedBatch) {
+		batch.result = router.sendBatch(service, batch)
+	})
+
+	select {
+	case <-batch.done:
+		return batch.result
+	case <-ctx.Done():
+		return Result{
+			Scheme: service.scheme,
+			URL:    service.url,
+			Err:    fmt.Errorf("failed to send using %v: %w", service.scheme, ctx.Err()),
+		}
+	}
+}
+
+// sendBatch sends the coalesced messages as a single notification, or as several if the service's message limits
+// would be exceeded otherwise. The batch is sent even if the callers that added the messages gave up waiting for it.
+func (router *ServiceRouter) sendBatch(service routedService, batch *coalescedBatch) Result {
+	message := strings.Join(batch.messages, "\n")
+
+	limited, ok := service.Service.(t.MessageLimiter)
+	if !ok {
+		return router.sendWithFailover(context.Background(), service, notification{message: message, params: batch.params})
+	}
+
+	limit := limited.MessageLimit()
+	if _, rich := service.Service.(t.RichSender); !rich {
+		// The items are joined into a single message, so it's only the total size that limits the number of lines
+		limit.ChunkCount = limit.TotalChunkSize
+	}
+
+	result := Result{Scheme: service.scheme, URL: service.url}
+	for _, items := range util.MessageItemsFromLines(message, limit) {
+		chunkResult := router.sendWithFailover(context.Background(), service, notification{items: items, params: batch.params})
+		chunkResult.Attempts += result.Attempts
+		if result.Err != nil {
+			chunkResult.Err = result.Err
+		}
+		result = chunkResult
+	}
+	return result
+}
+
+// sendWithFailover sends the notification using the service, and if that fails, using its fallbacks
+func (router *ServiceRouter) sendWithFailover(ctx context.Context, service routedService, n notification) Result {
+	result := router.sendWithRetries(ctx, service, n)
+
+	for _, fallback := range service.fallbacks {
+		if result.Err == nil || ctx.Err() != nil {
+			break
+		}
+		router.log(fmt.Sprintf("Sending using %v failed, falling back to %v:", result.Scheme, fallback.scheme), result.Err)
+
+		failures := append(result.Failures, result)
+		result.Failures = nil
+		result = router.sendWithRetries(ctx, fallback, n)
+		result.Failures = failures
+	}
+
+	return result
+}
+
+// sendWithRetries sends the message using the service, retrying according to its retry policy
+func (router *ServiceRouter) sendWithRetries(ctx context.Context, service routedService, n notification) Result {
+	policy := service.props.retryPolicy(router.RetryPolicy)
+	result := Result{
+		Scheme: service.scheme,
+		URL:    service.url,
+	}
+	start := time.Now()
+
+	info := ServiceInfo{Scheme: service.scheme, URL: service.url, Size: n.size()}
+	ctx = router.observers.serviceStarted(ctx, info)
+
+retry:
+	for {
+		if err := service.limiter.wait(ctx); err != nil {
+			result.Err = fmt.Errorf("failed to send using %v: %w", service.scheme, err)
+			break
+		}
+
+		result.Attempts++
+		result.Err = sendAttempt(ctx, service, router.Timeout, n)
+		router.observers.attemptFinished(ctx, info, result.Attempts, result.Err)
+
+		if result.Err == nil || result.Attempts >= policy.MaxAttempts || ctx.Err() != nil || !policy.retryable(result.Err) {
+			break
+		}
+
+		delay := policy.delay(result.Attempts, result.Err)
+		router.log(fmt.Sprintf("Attempt %d using %v failed, retrying in %v:", result.Attempts, service.scheme, delay), result.Err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			break retry
+		}
+	}
+
+	result.Duration = time.Since(start)
+	router.observers.serviceFinished(ctx, info, result)
+	return result
 }
 
-func sendToService(service t.Service, results chan error, timeout time.Duration, message string, params t.Params) {
-	result := make(chan error)
+// sendAttempt sends the notification using the service, giving up if it takes longer than timeout
+func sendAttempt(ctx context.Context, service routedService, timeout time.Duration, n notification) error {
+	// Buffered, so that the sending goroutine can exit even if nobody is waiting for the result
+	done := make(chan error, 1)
 
-	// TODO: There really ought to be a better way to name the services
-	pkg := reflect.TypeOf(service).Elem().PkgPath()
-	serviceName := pkg[strings.LastIndex(pkg, "/")+1:]
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	go func() { result <- service.Send(message, &params) }()
+	go func() { done <- n.sendUsing(ctx, service.Service) }()
 
 	select {
-	case res := <-result:
-		results <- res
-	case <-time.After(timeout):
-		results <- fmt.Errorf("failed to send using %v: timed out", serviceName)
+	case err := <-done:
+		return redactError(err, service.secrets)
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return timeoutError{scheme: service.scheme}
+		}
+		return fmt.Errorf("failed to send using %v: %w", service.scheme, ctx.Err())
 	}
 }
 
-// Enqueue adds the message to an internal queue and sends it when Flush is invoked
+// Enqueue adds the message to an internal queue and sends it when Flush is invoked.
+// If the router has an Outbox, the message is added to it instead.
 func (router *ServiceRouter) Enqueue(message string, v ...interface{}) {
 	if len(v) > 0 {
 		message = fmt.Sprintf(message, v...)
 	}
+	if router.Outbox != nil {
+		err := router.Outbox.enqueue(message)
+		if err == nil {
+			return
+		}
+		// Keep it in memory instead, it will still be sent on Flush
+		router.log("Failed to add message to outbox:", err)
+	}
 	router.queue = append(router.queue, message)
 }
 
 // Flush sends all messages that have been queued up as a combined message. This method should be deferred!
+// If the router has an Outbox, any notifications that could not be delivered earlier are also retried.
 func (router *ServiceRouter) Flush(params *t.Params) {
+	if router.Outbox != nil {
+		if err := router.flushOutbox(context.Background(), params); err != nil {
+			router.log("Failed to flush outbox:", err)
+		}
+		return
+	}
+
 	// Since this method is supposed to be deferred we just have to ignore errors
 	_ = router.Send(strings.Join(router.queue, "\n"), params)
 	router.queue = []string{}
 }
 
+func (router *ServiceRouter) flushOutbox(ctx context.Context, params *t.Params) error {
+	if params == nil {
+		params = &t.Params{}
+	}
+
+	err := router.Outbox.schedule(router.services, router.queue, *params, time.Now())
+	if err != nil {
+		return err
+	}
+	router.queue = []string{}
+
+	_, err = router.ReplayOutbox(ctx)
+	return err
+}
+
+// ReplayOutbox tries to deliver all pending notifications in the router's Outbox, in the order they were added.
+// Notifications that are older than the outbox TTL, have been tried more than the outbox MaxAttempts, or fail with an
+// error that is known to be permanent, like a request rejected by the service, are moved to the dead letters. Call this after creating the router to deliver notifications left over from a previous process.
+func (router *ServiceRouter) ReplayOutbox(ctx context.Context) ([]Result, error) {
+	if router.Outbox == nil {
+		return nil, nil
+	}
+
+	if err := router.Outbox.expire(time.Now()); err != nil {
+		return nil, err
+	}
+
+	pending := router.Outbox.Pending()
+	replays := make(chan outboxReplay, len(router.services))
+	for i, service := range router.services {
+		entries := []OutboxEntry{}
+		for _, entry := range pending {
+			if entry.Service == service.key {
+				entries = append(entries, entry)
+			}
+		}
+		go router.replayToService(ctx, i, service, entries, replays)
+	}
+
+	ordered := make([][]Result, len(router.services))
+	var err error
+	for range router.services {
+		replay := <-replays
+		ordered[replay.index] = replay.results
+		if replay.err != nil {
+			err = replay.err
+		}
+	}
+
+	results := []Result{}
+	for _, serviceResults := range ordered {
+		results = append(results, serviceResults...)
+	}
+	return results, err
+}
+
+// outboxReplay is the outcome of replaying the pending notifications for a single service
+type outboxReplay struct {
+	index   int
+	results []Result
+	err     error
+}
+
+// replayToService sends the entries one at a time, to keep them in order, stopping at the first one that can
+// be retried later
+func (router *ServiceRouter) replayToService(ctx context.Context, index int, service routedService, entries []OutboxEntry, replays chan outboxReplay) {
+	replay := outboxReplay{index: index}
+	results := make(chan Result, 1)
+
+	for _, entry := range entries {
+		params := entry.Params
+		if params == nil {
+			params = t.Params{}
+		}
+
+		router.sendToService(ctx, index, service, results, notification{message: entry.Message, params: params, replay: true})
+		result := <-results
+		replay.results = append(replay.results, result)
+
+		permanent := result.Err != nil && ctx.Err() == nil && IsPermanent(result.Err)
+		dead, err := router.Outbox.complete(entry.ID, result, permanent)
+		if err != nil {
+			replay.err = err
+		}
+		if result.Err != nil && !dead {
+			break
+		}
+	}
+
+	replays <- replay
+}
+
 // SetLogger sets the logger that the services will use to write progress logs
 func (router *ServiceRouter) SetLogger(logger t.StdLogger) {
 	router.logger = logger
 	for _, service := range router.services {
 		service.SetLogger(logger)
+		for _, fallback := range service.fallbacks {
+			fallback.SetLogger(logger)
+		}
 	}
 }
 
+// SetStructuredLogger sets the leveled logger that the router and services will use instead of the std logger. The
+// records of each service have the service scheme attached using the "service" key. It applies to both the services
+// already added and the ones added later. Passing nil restores the use of the std logger.
+func (router *ServiceRouter) SetStructuredLogger(logger t.StructuredLogger) {
+	router.structured = logger
+	for i := range router.services {
+		router.applyStructuredLogger(router.services[i])
+		for _, fallback := range router.services[i].fallbacks {
+			router.applyStructuredLogger(fallback)
+		}
+	}
+}
+
+// applyStructuredLogger sets the structured logger of the service, if it supports it
+func (router *ServiceRouter) applyStructuredLogger(service routedService) {
+	loggerService, ok := service.Service.(t.StructuredLoggerService)
+	if !ok {
+		return
+	}
+	if router.structured == nil {
+		loggerService.SetStructuredLogger(nil)
+	} else {
+		loggerService.SetStructuredLogger(util.LoggerWith(router.structured, "service", service.scheme))
+	}
+}
+
+// SetHTTPClient sets the client that the services will use for their HTTP requests, instead of http.DefaultClient.
+// It applies to both the services already added and the ones added later. Passing nil restores the default client.
+// Services using the TLS props get a copy of the client using their own TLS config.
+func (router *ServiceRouter) SetHTTPClient(client *http.Client) {
+	router.httpClient = client
+	for i := range router.services {
+		router.applyServiceTransport(&router.services[i])
+		for j := range router.services[i].fallbacks {
+			router.applyServiceTransport(&router.services[i].fallbacks[j])
+		}
+	}
+}
+
+func (router *ServiceRouter) applyServiceTransport(service *routedService) {
+	if err := router.applyTransport(service); err != nil {
+		router.log(fmt.Sprintf("Failed to set the HTTP client of %v: %v", service.url, err))
+	}
+}
+
+// SetTemplateFile loads the template in the file into the services that were already added to the router, including
+// the fallbacks, using the id. Which ids are used depends on the service, e.g. the generic service uses the id set
+// using its template prop.
+func (router *ServiceRouter) SetTemplateFile(id string, file string) error {
+	for _, service := range router.services {
+		if err := service.SetTemplateFile(id, file); err != nil {
+			return fmt.Errorf("failed to load template %q: %w", id, err)
+		}
+		for _, fallback := range service.fallbacks {
+			if err := fallback.SetTemplateFile(id, file); err != nil {
+				return fmt.Errorf("failed to load template %q: %w", id, err)
+			}
+		}
+	}
+	return nil
+}
+
 // ExtractServiceName from a notification URL
 func (router *ServiceRouter) ExtractServiceName(rawURL string) (string, *url.URL, error) {
 	serviceURL, err := url.Parse(rawURL)
@@ -174,8 +632,40 @@ func (router *ServiceRouter) Route(rawURL string, message string) error {
 }
 
 func (router *ServiceRouter) initService(rawURL string) (t.Service, error) {
+	routed, err := router.initRoutedService(rawURL)
+	if routed == nil {
+		return nil, err
+	}
+	return routed.Service, err
+}
+
+// initRoutedService creates and initializes the service for rawURL, after resolving its secret references
+func (router *ServiceRouter) initRoutedService(rawURL string) (*routedService, error) {
+	resolvedURL, secrets, err := resolveSecrets(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	routed, err := router.initResolvedService(rawURL, resolvedURL, secrets)
+	return routed, redactError(err, secrets)
+}
+
+// initResolvedService creates and initializes the service for the URL with its secret references resolved, after
+// extracting the router props from it. The unresolved URL is used for identifying the service.
+func (router *ServiceRouter) initResolvedService(rawURL string, resolvedURL string, secrets []string) (*routedService, error) {
+
+	scheme, configURL, err := router.ExtractServiceName(resolvedURL)
+	if err != nil {
+		return nil, redactURLError(err)
+	}
 
-	scheme, configURL, err := router.ExtractServiceName(rawURL)
+	redactedURL := format.RedactSecrets(configURL.Redacted(), secrets)
+	props, err := extractServiceProps(configURL, forwardsQuery(scheme))
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := props.tls.config()
 	if err != nil {
 		return nil, err
 	}
@@ -185,8 +675,21 @@ func (router *ServiceRouter) initService(rawURL string) (t.Service, error) {
 		return nil, err
 	}
 
-	if configURL.Scheme != scheme {
-		router.log("Got custom URL:", configURL.String())
+	routed := &routedService{
+		Service:   service,
+		scheme:    scheme,
+		url:       redactedURL,
+		key:       serviceKey(rawURL),
+		props:     props,
+		limiter:   newServiceLimiter(props.rateLimit, props.coalesce),
+		dedupe:    newDeduplicator(props.dedupe, props.dedupeSummary),
+		tlsConfig: tlsConfig,
+		secrets:   secrets,
+	}
+
+	custom := configURL.Scheme != scheme
+	if custom {
+		router.log("Got custom URL:", format.RedactSecrets(configURL.Redacted(), secrets))
 		customURLService, ok := service.(t.CustomURLService)
 		if !ok {
 			return nil, fmt.Errorf("custom URLs are not supported by '%s' service", scheme)
@@ -195,15 +698,24 @@ func (router *ServiceRouter) initService(rawURL string) (t.Service, error) {
 		if err != nil {
 			return nil, err
 		}
-		router.log("Converted service URL:", configURL.String())
+		if format.HasServiceConfig(service) {
+			router.log("Converted service URL:", format.RedactSecrets(format.RedactURL(format.GetServiceConfig(service), configURL), secrets))
+		}
+	}
+
+	if err = router.applyTransport(routed); err != nil {
+		return nil, err
 	}
 
+	router.applyStructuredLogger(*routed)
+
 	err = service.Initialize(configURL, router.logger)
+	routed.redactService(resolvedURL, custom)
 	if err != nil {
-		return service, err
+		return routed, redactError(err, routed.secrets)
 	}
 
-	return service, nil
+	return routed, nil
 }
 
 // NewService returns a new uninitialized service instance
@@ -211,26 +723,23 @@ func (*ServiceRouter) NewService(serviceScheme string) (t.Service, error) {
 	return newService(serviceScheme)
 }
 
-// newService returns a new uninitialized service instance
-func newService(serviceScheme string) (t.Service, error) {
-	serviceFactory, valid := serviceMap[strings.ToLower(serviceScheme)]
-	if !valid {
-		return nil, fmt.Errorf("unknown service %q", serviceScheme)
-	}
-	return serviceFactory(), nil
-}
-
-// ListServices returns the available services
+// ListServices returns the available services, including the ones added using RegisterService
 func (router *ServiceRouter) ListServices() []string {
-	services := make([]string, len(serviceMap))
+	return RegisteredServices()
+}
 
-	i := 0
-	for key := range serviceMap {
-		services[i] = key
-		i++
+// MessageLimit returns the message limits declared by the service at index, in the order the services were added,
+// without initializing it again. For failover groups, the limits of the primary service are returned. Returns false
+// if there is no such service, or it does not declare its limits.
+func (router *ServiceRouter) MessageLimit(index int) (t.MessageLimit, bool) {
+	if index < 0 || index >= len(router.services) {
+		return t.MessageLimit{}, false
 	}
-
-	return services
+	limiter, ok := router.services[index].Service.(t.MessageLimiter)
+	if !ok {
+		return t.MessageLimit{}, false
+	}
+	return limiter.MessageLimit(), true
 }
 
 // Locate returns the service implementation that corresponds to the given service URL
@@ -240,6 +749,10 @@ func (router *ServiceRouter) Locate(rawURL string) (t.Service, error) {
 }
 
 func (router *ServiceRouter) log(v ...interface{}) {
+	if router.structured != nil {
+		router.structured.Log(context.Background(), t.LogLevelInfo, strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+		return
+	}
 	if router.logger == nil {
 		return
 	}
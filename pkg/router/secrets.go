@@ -0,0 +1,205 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SecretResolver returns the values of secrets referenced in service URLs, like ${vault:notifications/smtp}
+type SecretResolver interface {
+	ResolveSecret(reference string) (string, error)
+}
+
+// SecretResolverFunc is a function used as a SecretResolver
+type SecretResolverFunc func(reference string) (string, error)
+
+// ResolveSecret calls the function with the reference
+func (f SecretResolverFunc) ResolveSecret(reference string) (string, error) {
+	return f(reference)
+}
+
+// CommandSecretResolver runs the command given as the reference, and returns what it writes to stdout. It is not
+// registered by default, since anyone able to supply service URLs could then run commands.
+var CommandSecretResolver SecretResolver = SecretResolverFunc(resolveCommandSecret)
+
+// commandSecretTimeout is how long the command of a command secret reference is allowed to run
+const commandSecretTimeout = 10 * time.Second
+
+// secretResolversMutex guards secretResolvers, which is the registry of the resolvers for each kind of reference
+var secretResolversMutex sync.RWMutex
+
+var secretResolvers = map[string]SecretResolver{
+	"env":  SecretResolverFunc(resolveEnvSecret),
+	"file": SecretResolverFunc(resolveFileSecret),
+}
+
+// secretReferencePattern matches secret references, like ${env:SMTP_PASS}, capturing the kind and the reference
+var secretReferencePattern = regexp.MustCompile(`\$\{([a-z][a-z0-9.-]*):([^}]*)}`)
+
+// RegisterSecretResolver adds the resolver to the registry, using it for the secret references of the kind, e.g.
+// "vault" for ${vault:notifications/smtp}. An error is returned if the kind is invalid or if a resolver has already
+// been registered for it. Registered resolvers are used by all routers, for every service URL added after that.
+func RegisterSecretResolver(kind string, resolver SecretResolver) error {
+	kind = strings.ToLower(kind)
+	if !schemePattern.MatchString(kind) {
+		return fmt.Errorf("invalid secret reference kind %q", kind)
+	}
+	if resolver == nil {
+		return fmt.Errorf("no resolver supplied for secret reference kind %q", kind)
+	}
+
+	secretResolversMutex.Lock()
+	defer secretResolversMutex.Unlock()
+
+	if _, found := secretResolvers[kind]; found {
+		return fmt.Errorf("secret reference kind %q is already registered", kind)
+	}
+	secretResolvers[kind] = resolver
+
+	return nil
+}
+
+// UnregisterSecretResolver removes the resolver for the kind from the registry, returning whether it was registered
+func UnregisterSecretResolver(kind string) bool {
+	secretResolversMutex.Lock()
+	defer secretResolversMutex.Unlock()
+
+	kind = strings.ToLower(kind)
+	_, found := secretResolvers[kind]
+	delete(secretResolvers, kind)
+	return found
+}
+
+// urlComponent is the part of a URL that a secret reference is in, deciding which characters of the secret are escaped
+type urlComponent int
+
+const (
+	componentUserinfo urlComponent = iota
+	componentHost
+	componentPath
+	componentQuery
+)
+
+// resolveSecrets returns rawURL with the secret references replaced by the values of the secrets, escaped for the part
+// of the URL they are in, along with the secrets, in both their plain and escaped forms, for redacting them
+func resolveSecrets(rawURL string) (string, []string, error) {
+	matches := secretReferencePattern.FindAllStringSubmatchIndex(rawURL, -1)
+	if len(matches) == 0 {
+		return rawURL, nil, nil
+	}
+
+	// The references can contain URL delimiters themselves, like the path of a file, so they are masked when
+	// looking for the part of the URL they are in
+	masked := secretReferencePattern.ReplaceAllStringFunc(rawURL, func(match string) string {
+		return strings.Repeat("x", len(match))
+	})
+
+	var secrets []string
+	sb := strings.Builder{}
+	last := 0
+	for _, match := range matches {
+		kind, reference := rawURL[match[2]:match[3]], rawURL[match[4]:match[5]]
+
+		secretResolversMutex.RLock()
+		resolver, found := secretResolvers[kind]
+		secretResolversMutex.RUnlock()
+
+		if !found {
+			return "", nil, fmt.Errorf("unknown secret reference kind %q", kind)
+		}
+		secret, err := resolver.ResolveSecret(reference)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to resolve secret %q: %w", rawURL[match[0]:match[1]], err)
+		}
+
+		escaped := escapeSecret(secret, componentAt(masked, match[0]))
+		secrets = append(secrets, secret, escaped)
+		sb.WriteString(rawURL[last:match[0]])
+		sb.WriteString(escaped)
+		last = match[1]
+	}
+	sb.WriteString(rawURL[last:])
+
+	return sb.String(), secrets, nil
+}
+
+// componentAt returns the part of the URL that the offset is in
+func componentAt(rawURL string, offset int) urlComponent {
+	authorityStart := 0
+	if i := strings.Index(rawURL, "://"); i >= 0 {
+		authorityStart = i + len("://")
+	}
+	authorityEnd := len(rawURL)
+	if i := strings.IndexAny(rawURL[authorityStart:], "/?#"); i >= 0 {
+		authorityEnd = authorityStart + i
+	}
+
+	if offset < authorityEnd {
+		if at := strings.LastIndex(rawURL[authorityStart:authorityEnd], "@"); at >= 0 && offset < authorityStart+at {
+			return componentUserinfo
+		}
+		return componentHost
+	}
+	if i := strings.IndexAny(rawURL[authorityEnd:], "?#"); i < 0 || offset < authorityEnd+i {
+		return componentPath
+	}
+	return componentQuery
+}
+
+// escapeSecret percent-encodes the characters of the secret that would change the meaning of the URL component. The
+// colon is kept in the userinfo, so that a secret can contain both the user and the password, like the telegram
+// tokens, and the slash is kept in the path, so that a secret can contain several path segments.
+func escapeSecret(secret string, component urlComponent) string {
+	sb := strings.Builder{}
+	for _, b := range []byte(secret) {
+		if isUnreserved(b) || component == componentUserinfo && b == ':' || component == componentPath && b == '/' {
+			sb.WriteByte(b)
+		} else {
+			sb.WriteString(fmt.Sprintf("%%%02X", b))
+		}
+	}
+	return sb.String()
+}
+
+func isUnreserved(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9' ||
+		b == '-' || b == '.' || b == '_' || b == '~'
+}
+
+func resolveEnvSecret(name string) (string, error) {
+	value, found := os.LookupEnv(name)
+	if !found {
+		return "", fmt.Errorf("environment variable %q is not set", name)
+	}
+	return value, nil
+}
+
+func resolveFileSecret(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+func resolveCommandSecret(command string) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", fmt.Errorf("no command supplied")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandSecretTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, args[0], args[1:]...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(output), "\r\n"), nil
+}
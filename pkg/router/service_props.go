@@ -0,0 +1,240 @@
+package router
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/format"
+	"github.com/containrrr/shoutrrr/pkg/services/generic"
+	t "github.com/containrrr/shoutrrr/pkg/types"
+)
+
+const (
+	// RetriesKey is the common query key for the number of times a failed notification is retried
+	RetriesKey = "retries"
+	// BackoffKey is the common query key for the delay before the first retry (e.g. "2s")
+	BackoffKey = "backoff"
+	// MinLevelKey is the common query key for the lowest level of message items sent to the service (e.g. "warning")
+	MinLevelKey = "minlevel"
+	// LevelsKey is the common query key for the comma separated levels of message items sent to the service
+	LevelsKey = "levels"
+	// RateLimitKey is the common query key for the maximum rate of requests made using the service (e.g. "20/min")
+	RateLimitKey = "ratelimit"
+	// CoalesceKey is the common query key for the window in which messages are combined into one (e.g. "10s")
+	CoalesceKey = "coalesce"
+	// DedupeKey is the common query key for the window in which duplicate notifications are suppressed (e.g. "10m")
+	DedupeKey = "dedupe"
+	// DedupeSummaryKey is the common query key for sending a summary of the suppressed duplicates after the window
+	DedupeSummaryKey = "dedupesummary"
+	// CAFileKey is the common query key for a PEM file with additional CA certificates used to verify the server
+	CAFileKey = "cafile"
+	// CertFileKey is the common query key for a PEM file with the client certificate used for mutual TLS
+	CertFileKey = "certfile"
+	// KeyFileKey is the common query key for a PEM file with the private key of the client certificate
+	KeyFileKey = "keyfile"
+	// InsecureKey is the common query key for skipping the verification of the server certificate
+	InsecureKey = "insecure"
+	// ServerNameKey is the common query key for the server name used for SNI and verifying the server certificate
+	ServerNameKey = "servername"
+	// ProxyKey is the common query key for the URL of the HTTP or SOCKS5 proxy used by the service, or "none" to not
+	// use a proxy, even if one has been set using the environment
+	ProxyKey = "proxy"
+)
+
+// PropPrefix can be prepended to the common query keys, to tell them apart from the query params of the service. For
+// services that pass unknown query params on, like generic, the common query keys are only used with the prefix.
+const PropPrefix = "shoutrrr_"
+
+// noProxyValue is the value of the proxy prop that disables the use of a proxy
+const noProxyValue = "none"
+
+// serviceProps are the props that are handled by the router instead of the service. They can be set for each
+// service using the common query keys, optionally prefixed by PropPrefix, which are removed from the URL before it is
+// passed on to the service.
+type serviceProps struct {
+	// retries is the number of retries, or -1 if not set
+	retries int
+	// backoff is the delay before the first retry, or 0 if not set
+	backoff time.Duration
+	// levels are the levels of the message items that are sent to the service
+	levels levelFilter
+	// rateLimit is the maximum rate of requests, or the zero value if not limited
+	rateLimit rateLimit
+	// coalesce is the window in which messages are combined, or 0 if not set
+	coalesce time.Duration
+	// dedupe is the window in which duplicates are suppressed, or 0 if not set
+	dedupe time.Duration
+	// dedupeSummary is whether a summary is sent for suppressed duplicates
+	dedupeSummary bool
+	// tls are the props used to configure the TLS connections
+	tls tlsProps
+	// proxy is the URL of the proxy, or nil if not set
+	proxy *url.URL
+	// noProxy is whether connections are made directly, ignoring the proxy set using the environment
+	noProxy bool
+}
+
+// extractServiceProps removes the common query keys from serviceURL and returns the parsed props. If prefixedOnly is
+// set, only the keys prefixed by PropPrefix are used, leaving the other query params to the service.
+func extractServiceProps(serviceURL *url.URL, prefixedOnly bool) (serviceProps, error) {
+	props := serviceProps{
+		retries: -1,
+	}
+
+	query := serviceURL.Query()
+	removed := false
+
+	for key, values := range query {
+		value := values[0]
+		propKey := strings.ToLower(key)
+		if strings.HasPrefix(propKey, PropPrefix) {
+			propKey = propKey[len(PropPrefix):]
+		} else if prefixedOnly {
+			continue
+		}
+
+		switch propKey {
+		case RetriesKey:
+			retries, err := strconv.Atoi(value)
+			if err != nil || retries < 0 {
+				return props, fmt.Errorf("invalid value for %q: %q", RetriesKey, value)
+			}
+			props.retries = retries
+		case BackoffKey:
+			backoff, err := time.ParseDuration(value)
+			if err != nil || backoff < 0 {
+				return props, fmt.Errorf("invalid value for %q: %q", BackoffKey, value)
+			}
+			props.backoff = backoff
+		case MinLevelKey:
+			if props.levels != 0 {
+				return props, fmt.Errorf("%q and %q can not be used together", MinLevelKey, LevelsKey)
+			}
+			minLevel, err := t.ParseMessageLevel(value)
+			if err != nil {
+				return props, err
+			}
+			for level := minLevel; int(level) < t.MessageLevelCount; level++ {
+				props.levels = props.levels.with(level)
+			}
+		case LevelsKey:
+			if props.levels != 0 {
+				return props, fmt.Errorf("%q and %q can not be used together", MinLevelKey, LevelsKey)
+			}
+			for _, name := range strings.Split(value, ",") {
+				level, err := t.ParseMessageLevel(strings.TrimSpace(name))
+				if err != nil {
+					return props, err
+				}
+				props.levels = props.levels.with(level)
+			}
+		case RateLimitKey:
+			limit, err := parseRateLimit(value)
+			if err != nil {
+				return props, err
+			}
+			props.rateLimit = limit
+		case CoalesceKey:
+			window, err := time.ParseDuration(value)
+			if err != nil || window < 0 {
+				return props, fmt.Errorf("invalid value for %q: %q", CoalesceKey, value)
+			}
+			props.coalesce = window
+		case DedupeKey:
+			window, err := time.ParseDuration(value)
+			if err != nil || window < 0 {
+				return props, fmt.Errorf("invalid value for %q: %q", DedupeKey, value)
+			}
+			props.dedupe = window
+		case DedupeSummaryKey:
+			summary, ok := format.ParseBool(value, false)
+			if !ok {
+				return props, fmt.Errorf("invalid value for %q: %q", DedupeSummaryKey, value)
+			}
+			props.dedupeSummary = summary
+		case CAFileKey:
+			props.tls.caFile = value
+		case CertFileKey:
+			props.tls.certFile = value
+		case KeyFileKey:
+			props.tls.keyFile = value
+		case InsecureKey:
+			insecure, ok := format.ParseBool(value, false)
+			if !ok {
+				return props, fmt.Errorf("invalid value for %q: %q", InsecureKey, value)
+			}
+			props.tls.insecure = insecure
+		case ServerNameKey:
+			props.tls.serverName = value
+		case ProxyKey:
+			if strings.EqualFold(value, noProxyValue) {
+				props.noProxy = true
+				break
+			}
+			proxy, err := parseProxy(value)
+			if err != nil {
+				return props, err
+			}
+			props.proxy = proxy
+		default:
+			continue
+		}
+		query.Del(key)
+		removed = true
+	}
+
+	// Only re-encode the query if needed, to keep the URL passed to the service as close to the original as possible
+	if removed {
+		serviceURL.RawQuery = query.Encode()
+	}
+
+	return props, nil
+}
+
+// forwardsQuery returns whether the service passes the query params it does not use on, like the generic service
+// adding them to the webhook URL, in which case only the prefixed common query keys are used by the router
+func forwardsQuery(scheme string) bool {
+	return scheme == generic.Scheme
+}
+
+// retryPolicy returns the base policy with any of the retry props that have been set applied
+func (props *serviceProps) retryPolicy(base RetryPolicy) RetryPolicy {
+	policy := base
+	if props.retries >= 0 {
+		policy.MaxAttempts = props.retries + 1
+	}
+	if props.backoff > 0 {
+		policy.Backoff = props.backoff
+	}
+	return policy
+}
+
+// levelFilter is the set of message levels that are sent to a service, where the zero value means every level
+type levelFilter uint32
+
+func (filter levelFilter) with(level t.MessageLevel) levelFilter {
+	return filter | 1<<level
+}
+
+// matches returns whether items of the level should be sent. Items without a level are always sent.
+func (filter levelFilter) matches(level t.MessageLevel) bool {
+	return filter == 0 || level == t.Unknown || filter&(1<<level) != 0
+}
+
+// filter returns the items that match the filter
+func (filter levelFilter) filter(items []t.MessageItem) []t.MessageItem {
+	if filter == 0 {
+		return items
+	}
+
+	matching := make([]t.MessageItem, 0, len(items))
+	for _, item := range items {
+		if filter.matches(item.Level) {
+			matching = append(matching, item)
+		}
+	}
+	return matching
+}
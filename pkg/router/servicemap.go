@@ -24,7 +24,7 @@ import (
 	t "github.com/containrrr/shoutrrr/pkg/types"
 )
 
-var serviceMap = map[string]func() t.Service{
+var serviceMap = map[string]ServiceFactory{
 	"bark":       func() t.Service { return &bark.Service{} },
 	"discord":    func() t.Service { return &discord.Service{} },
 	"generic":    func() t.Service { return &generic.Service{} },
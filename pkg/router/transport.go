@@ -0,0 +1,177 @@
+package router
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+
+	t "github.com/containrrr/shoutrrr/pkg/types"
+	"golang.org/x/net/proxy"
+)
+
+// tlsProps are the service props used to configure the TLS connections made by the service
+type tlsProps struct {
+	caFile     string
+	certFile   string
+	keyFile    string
+	insecure   bool
+	serverName string
+}
+
+// isSet returns whether any of the TLS props have been set
+func (props tlsProps) isSet() bool {
+	return props != tlsProps{}
+}
+
+// config loads the files referenced by the props and returns the resulting TLS config, or nil if no props are set
+func (props tlsProps) config() (*tls.Config, error) {
+	if !props.isSet() {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		ServerName:         props.serverName,
+		InsecureSkipVerify: props.insecure,
+	}
+
+	if props.caFile != "" {
+		pem, err := os.ReadFile(props.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %q: %w", CAFileKey, err)
+		}
+
+		// The CA certificates are added to the system ones, so that public servers can still be verified
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %q file %q", CAFileKey, props.caFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if props.certFile != "" || props.keyFile != "" {
+		if props.certFile == "" || props.keyFile == "" {
+			return nil, fmt.Errorf("%q and %q must be used together", CertFileKey, KeyFileKey)
+		}
+		cert, err := tls.LoadX509KeyPair(props.certFile, props.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// parseProxy parses the value of the proxy prop, which must be an HTTP(S) or SOCKS5 URL
+func parseProxy(value string) (*url.URL, error) {
+	proxyURL, err := url.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %q: %w", ProxyKey, err)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid value for %q: unsupported proxy scheme %q", ProxyKey, proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid value for %q: missing proxy host", ProxyKey)
+	}
+
+	return proxyURL, nil
+}
+
+// applyTransport sets the HTTP client, TLS config and dialer used by the service, based on the HTTP client of the
+// router and the TLS and proxy props of the service
+func (router *ServiceRouter) applyTransport(service *routedService) error {
+	client, err := serviceHTTPClient(router.httpClient, service)
+	if err != nil {
+		return err
+	}
+	setHTTPClient(service.Service, client)
+
+	if service.tlsConfig != nil {
+		if tlsService, ok := service.Service.(t.TLSConfigService); ok {
+			tlsService.SetTLSConfig(service.tlsConfig)
+		}
+	}
+
+	if service.props.proxy != nil {
+		if dialerService, ok := service.Service.(t.DialerService); ok {
+			dialer, err := proxyDialer(service.props.proxy)
+			if err != nil {
+				return err
+			}
+			dialerService.SetDialer(dialer)
+		}
+	}
+
+	return nil
+}
+
+// serviceHTTPClient returns a copy of base using the TLS config and proxy of the service, or base itself if the
+// service uses neither. If base is nil, http.DefaultClient is used.
+func serviceHTTPClient(base *http.Client, service *routedService) (*http.Client, error) {
+	if service.tlsConfig == nil && service.props.proxy == nil && !service.props.noProxy {
+		return base, nil
+	}
+
+	if base == nil {
+		base = http.DefaultClient
+	}
+
+	roundTripper := base.Transport
+	if roundTripper == nil {
+		roundTripper = http.DefaultTransport
+	}
+	transport, ok := roundTripper.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("the TLS and proxy props can only be used with an HTTP client using an *http.Transport, not %T", roundTripper)
+	}
+
+	transport = transport.Clone()
+	if service.tlsConfig != nil {
+		transport.TLSClientConfig = service.tlsConfig.Clone()
+	}
+	if service.props.proxy != nil {
+		transport.Proxy = http.ProxyURL(service.props.proxy)
+	} else if service.props.noProxy {
+		transport.Proxy = nil
+	}
+
+	client := *base
+	client.Transport = transport
+	return &client, nil
+}
+
+// proxyDialer returns a dialer making its connections using the SOCKS5 proxy at proxyURL
+func proxyDialer(proxyURL *url.URL) (t.ContextDialer, error) {
+	if proxyURL.Scheme != "socks5" && proxyURL.Scheme != "socks5h" {
+		return nil, fmt.Errorf("only SOCKS5 proxies can be used for connections that are not made using HTTP, not %q", proxyURL.Scheme)
+	}
+
+	dialer, err := proxy.FromURL(proxyURL, &net.Dialer{})
+	if err != nil {
+		return nil, err
+	}
+
+	contextDialer, ok := dialer.(proxy.ContextDialer)
+	if !ok {
+		return nil, fmt.Errorf("the proxy dialer does not support contexts")
+	}
+	return contextDialer, nil
+}
+
+// setHTTPClient sets the HTTP client of service, if it supports replacing it
+func setHTTPClient(service t.Service, client *http.Client) {
+	if clientService, ok := service.(t.HTTPClientService); ok {
+		clientService.SetHTTPClient(client)
+	}
+}
@@ -1,6 +1,7 @@
 package bark
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -21,13 +22,18 @@ type Service struct {
 
 // Send a notification message to Bark
 func (service *Service) Send(message string, params *types.Params) error {
-	config := service.config
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to Bark, aborting the request when ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	config := *service.config
 
-	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
 		return err
 	}
 
-	if err := service.sendAPI(config, message); err != nil {
+	if err := service.sendAPI(ctx, &config, message); err != nil {
 		return fmt.Errorf("failed to send bark notification: %w", err)
 	}
 
@@ -46,7 +52,7 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 
 }
 
-func (service *Service) sendAPI(config *Config, message string) error {
+func (service *Service) sendAPI(ctx context.Context, config *Config, message string) error {
 	response := apiResponse{}
 	request := PushPayload{
 		Body:      message,
@@ -60,9 +66,9 @@ func (service *Service) sendAPI(config *Config, message string) error {
 		Icon:      config.Icon,
 		URL:       config.URL,
 	}
-	jsonClient := jsonclient.NewClient()
+	jsonClient := jsonclient.NewWithHTTPClient(service.GetHTTPClient())
 
-	if err := jsonClient.Post(config.GetAPIURL("push"), &request, &response); err != nil {
+	if err := jsonClient.PostContext(ctx, config.GetAPIURL("push"), &request, &response); err != nil {
 		if jsonClient.ErrorResponse(err, &response) {
 			// apiResponse implements Error
 			return &response
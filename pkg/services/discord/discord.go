@@ -2,6 +2,7 @@ package discord
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -34,16 +35,21 @@ const (
 
 // Send a notification message to discord
 func (service *Service) Send(message string, params *types.Params) error {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to discord, aborting the request when ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
 	var firstErr error
 
 	if service.config.JSON {
 		postURL := CreateAPIURLFromConfig(service.config)
-		firstErr = doSend([]byte(message), postURL)
+		firstErr = service.doSend(ctx, []byte(message), postURL)
 	} else {
 		batches := CreateItemsFromPlain(message, service.config.SplitLines)
 		for _, items := range batches {
-			if err := service.sendItems(items, params); err != nil {
-				service.Log(err)
+			if err := service.SendItemsContext(ctx, items, params); err != nil {
+				service.LogAt(types.LogLevelError, "send", err.Error(), "Failed to send batch", "error", err)
 				if firstErr == nil {
 					firstErr = err
 				}
@@ -52,17 +58,23 @@ func (service *Service) Send(message string, params *types.Params) error {
 	}
 
 	if firstErr != nil {
-		return fmt.Errorf("failed to send discord notification: %v", firstErr)
+		return fmt.Errorf("failed to send discord notification: %w", firstErr)
 	}
 	return nil
 }
 
+// MessageLimit returns the payload limits of the discord webhook API
+func (service *Service) MessageLimit() types.MessageLimit {
+	return limits
+}
+
 // SendItems sends items with additional meta data and richer appearance
 func (service *Service) SendItems(items []types.MessageItem, params *types.Params) error {
-	return service.sendItems(items, params)
+	return service.SendItemsContext(context.Background(), items, params)
 }
 
-func (service *Service) sendItems(items []types.MessageItem, params *types.Params) error {
+// SendItemsContext sends items with additional meta data and richer appearance, aborting the request when ctx is done
+func (service *Service) SendItemsContext(ctx context.Context, items []types.MessageItem, params *types.Params) error {
 	var err error
 
 	config := *service.config
@@ -86,7 +98,7 @@ func (service *Service) sendItems(items []types.MessageItem, params *types.Param
 	}
 
 	postURL := CreateAPIURLFromConfig(&config)
-	return doSend(payloadBytes, postURL)
+	return service.doSend(ctx, payloadBytes, postURL)
 }
 
 // CreateItemsFromPlain creates a set of MessageItems that is compatible with Discords webhook payload
@@ -133,15 +145,21 @@ func CreateAPIURLFromConfig(config *Config) string {
 		config.Token)
 }
 
-func doSend(payload []byte, postURL string) error {
-	res, err := http.Post(postURL, "application/json", bytes.NewBuffer(payload))
+func (service *Service) doSend(ctx context.Context, payload []byte, postURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, postURL, bytes.NewBuffer(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := service.GetHTTPClient().Do(req)
 
 	if res == nil && err == nil {
 		err = fmt.Errorf("unknown error")
 	}
 
 	if err == nil && res.StatusCode != http.StatusNoContent {
-		err = fmt.Errorf("response status code %s", res.Status)
+		err = util.NewStatusError(res, fmt.Errorf("response status code %s", res.Status))
 	}
 
 	return err
@@ -60,6 +60,10 @@ var _ = Describe("the discord service", func() {
 			var impl types.Service = service
 			Expect(impl).ToNot(BeNil())
 		})
+		It("should implement RichSender interface", func() {
+			var impl types.RichSender = service
+			Expect(impl).ToNot(BeNil())
+		})
 	})
 	Describe("creating a config", func() {
 		When("given an url and a message", func() {
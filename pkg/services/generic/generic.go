@@ -5,8 +5,10 @@ import (
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
 
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -23,6 +25,11 @@ type Service struct {
 
 // Send a notification message to a generic webhook endpoint
 func (service *Service) Send(message string, paramsPtr *types.Params) error {
+	return service.SendContext(context.Background(), message, paramsPtr)
+}
+
+// SendContext sends a notification message to a generic webhook endpoint, aborting the request when ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, paramsPtr *types.Params) error {
 	config := *service.config
 
 	var params types.Params
@@ -33,14 +40,14 @@ func (service *Service) Send(message string, paramsPtr *types.Params) error {
 	}
 
 	if err := service.pkr.UpdateConfigFromParams(&config, &params); err != nil {
-		service.Logf("Failed to update params: %v", err)
+		service.LogAt(types.LogLevelWarn, "send", fmt.Sprintf("Failed to update params: %v", err), "Failed to update params", "error", err)
 	}
 
 	// Create a mutable copy of the passed params
 	sendParams := createSendParams(&config, params, message)
 
-	if err := service.doSend(&config, sendParams); err != nil {
-		return fmt.Errorf("an error occurred while sending notification to generic webhook: %s", err.Error())
+	if err := service.doSend(ctx, &config, sendParams); err != nil {
+		return fmt.Errorf("an error occurred while sending notification to generic webhook: %w", err)
 	}
 
 	return nil
@@ -69,14 +76,14 @@ func (*Service) GetConfigURLFromCustom(customURL *url.URL) (serviceURL *url.URL,
 	return config.getURL(&pkr), nil
 }
 
-func (service *Service) doSend(config *Config, params types.Params) error {
+func (service *Service) doSend(ctx context.Context, config *Config, params types.Params) error {
 	postURL := config.WebhookURL().String()
 	payload, err := service.getPayload(config, params)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequest(config.RequestMethod, postURL, payload)
+	req, err := http.NewRequestWithContext(ctx, config.RequestMethod, postURL, payload)
 	if err == nil {
 		req.Header.Set("Content-Type", config.ContentType)
 		req.Header.Set("Accept", config.ContentType)
@@ -84,15 +91,15 @@ func (service *Service) doSend(config *Config, params types.Params) error {
 			req.Header.Set(key, value)
 		}
 		var res *http.Response
-		res, err = http.DefaultClient.Do(req)
+		res, err = service.GetHTTPClient().Do(req)
 		if res != nil && res.Body != nil {
 			defer res.Body.Close()
 			if body, errRead := io.ReadAll(res.Body); errRead == nil {
-				service.Log("Server response: ", string(body))
+				service.LogAt(types.LogLevelDebug, "send", "Server response: "+string(body), "Server response", "body", string(body))
 			}
 		}
 		if err == nil && res.StatusCode >= http.StatusMultipleChoices {
-			err = fmt.Errorf("server returned response status code %s", res.Status)
+			err = util.NewStatusError(res, fmt.Errorf("server returned response status code %s", res.Status))
 		}
 	}
 
@@ -2,38 +2,89 @@ package googlechat
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 
+	"github.com/containrrr/shoutrrr/pkg/format"
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
 )
 
+// limits are the message limits of the Google Chat API, which allows 4096 characters of text per message.
+var limits = types.MessageLimit{
+	ChunkSize:      4096,
+	TotalChunkSize: 4096,
+	ChunkCount:     100,
+}
+
 // Service providing Google Chat as a notification service.
 type Service struct {
 	standard.Standard
 	config *Config
+	pkr    format.PropKeyResolver
 }
 
 // Initialize loads ServiceConfig from configURL and sets logger for this Service.
 func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) error {
 	service.Logger.SetLogger(logger)
 	service.config = &Config{}
+	service.pkr = format.NewPropKeyResolver(service.config)
 
-	err := service.config.SetURL(configURL)
+	if err := service.pkr.SetDefaultProps(service.config); err != nil {
+		return err
+	}
 
-	return err
+	return service.config.setURL(&service.pkr, configURL)
 }
 
 // Send a notification message to Google Chat.
-func (service *Service) Send(message string, _ *types.Params) error {
-	config := service.config
+func (service *Service) Send(message string, params *types.Params) error {
+	return service.SendContext(context.Background(), message, params)
+}
 
-	jsonBody, err := json.Marshal(JSON{
+// SendContext sends a notification message to Google Chat, aborting the request when ctx is done.
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	config := *service.config
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return err
+	}
+
+	if config.Title != "" {
+		message = fmt.Sprintf("*%s*\n%s", config.Title, message)
+	}
+
+	return service.send(ctx, &config, JSON{
 		Text: message,
 	})
+}
+
+// MessageLimit returns the payload limits of the Google Chat API.
+func (service *Service) MessageLimit() types.MessageLimit {
+	return limits
+}
+
+// SendItems sends items to Google Chat as a card, with a section for each item.
+func (service *Service) SendItems(items []types.MessageItem, params *types.Params) error {
+	return service.SendItemsContext(context.Background(), items, params)
+}
+
+// SendItemsContext sends items to Google Chat as a card, with a section for each item, aborting the request when
+// ctx is done.
+func (service *Service) SendItemsContext(ctx context.Context, items []types.MessageItem, params *types.Params) error {
+	config := *service.config
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return err
+	}
+
+	return service.send(ctx, &config, CreateJSONFromItems(&config, items))
+}
+
+func (service *Service) send(ctx context.Context, config *Config, payload JSON) error {
+	jsonBody, err := json.Marshal(payload)
 	if err != nil {
 		return err
 	}
@@ -41,15 +92,21 @@ func (service *Service) Send(message string, _ *types.Params) error {
 	postURL := getAPIURL(config)
 
 	jsonBuffer := bytes.NewBuffer(jsonBody)
-	resp, err := http.Post(postURL.String(), "application/json", jsonBuffer)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, postURL.String(), jsonBuffer)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := service.GetHTTPClient().Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send notification to Google Chat: %s", err)
+		return fmt.Errorf("failed to send notification to Google Chat: %w", err)
 	}
 
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("Google Chat API notification returned %d HTTP status code", resp.StatusCode)
+		return util.NewStatusError(resp, fmt.Errorf("Google Chat API notification returned %d HTTP status code", resp.StatusCode))
 	}
 
 	return nil
@@ -14,8 +14,24 @@ type Config struct {
 	standard.EnumlessConfig
 	Host  string `default:"chat.googleapis.com"`
 	Path  string
-	Token string
-	Key   string
+	Token string `secret:""`
+	Key   string `secret:""`
+
+	Title      string `key:"title" optional:"" desc:"Title of the card used for message items, or prepended to plain messages"`
+	ColorError string `key:"colorError" default:"#d60510" desc:"Text color of the level for error message items"`
+	ColorWarn  string `key:"colorWarn" default:"#ffc441" desc:"Text color of the level for warning message items"`
+	ColorInfo  string `key:"colorInfo" default:"#2488ff" desc:"Text color of the level for info message items"`
+	ColorDebug string `key:"colorDebug" default:"#7b00ab" desc:"Text color of the level for debug message items"`
+}
+
+// LevelColors returns an array of colors with a MessageLevel index
+func (config *Config) LevelColors() (colors [types.MessageLevelCount]string) {
+	colors[types.Error] = config.ColorError
+	colors[types.Warning] = config.ColorWarn
+	colors[types.Info] = config.ColorInfo
+	colors[types.Debug] = config.ColorDebug
+
+	return colors
 }
 
 // GetURL returns a URL representation of it's current field values
@@ -31,7 +47,7 @@ func (config *Config) SetURL(url *url.URL) error {
 }
 
 // SetURL updates a ServiceConfig from a URL representation of it's field values.
-func (config *Config) setURL(_ types.ConfigQueryResolver, serviceURL *url.URL) error {
+func (config *Config) setURL(resolver types.ConfigQueryResolver, serviceURL *url.URL) error {
 	config.Host = serviceURL.Host
 	config.Path = serviceURL.Path
 
@@ -39,6 +55,15 @@ func (config *Config) setURL(_ types.ConfigQueryResolver, serviceURL *url.URL) e
 	config.Key = query.Get("key")
 	config.Token = query.Get("token")
 
+	for key, vals := range query {
+		if key == "key" || key == "token" {
+			continue
+		}
+		if err := resolver.Set(key, vals[0]); err != nil {
+			return err
+		}
+	}
+
 	if config.Key == "" {
 		return errors.New("missing field 'key'")
 	}
@@ -50,8 +75,8 @@ func (config *Config) setURL(_ types.ConfigQueryResolver, serviceURL *url.URL) e
 	return nil
 }
 
-func (config *Config) getURL(_ types.ConfigQueryResolver) *url.URL {
-	query := url.Values{}
+func (config *Config) getURL(resolver types.ConfigQueryResolver) *url.URL {
+	query := format.BuildQueryWithCustomFields(resolver, url.Values{})
 	query.Set("key", config.Key)
 	query.Set("token", config.Token)
 
@@ -1,6 +1,87 @@
 package googlechat
 
+import (
+	"fmt"
+	"html"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+)
+
 // JSON is the actual payload being sent to the Google Chat API.
 type JSON struct {
+	Text  string `json:"text,omitempty"`
+	Cards []card `json:"cards,omitempty"`
+}
+
+type card struct {
+	Header   *cardHeader   `json:"header,omitempty"`
+	Sections []cardSection `json:"sections"`
+}
+
+type cardHeader struct {
+	Title string `json:"title"`
+}
+
+type cardSection struct {
+	Widgets []widget `json:"widgets"`
+}
+
+type widget struct {
+	TextParagraph *textParagraph `json:"textParagraph,omitempty"`
+	KeyValue      *keyValue      `json:"keyValue,omitempty"`
+}
+
+type textParagraph struct {
 	Text string `json:"text"`
 }
+
+type keyValue struct {
+	TopLabel string `json:"topLabel"`
+	Content  string `json:"content"`
+}
+
+// CreateJSONFromItems creates a payload with a card containing a section for each item, starting with its
+// colored level and timestamp, followed by its text and fields. The card has a header if the config has a title.
+func CreateJSONFromItems(config *Config, items []types.MessageItem) JSON {
+	colors := config.LevelColors()
+	sections := make([]cardSection, 0, len(items))
+
+	for _, item := range items {
+		var widgets []widget
+
+		header := ""
+		if item.Level != types.Unknown && int(item.Level) < len(colors) {
+			header = fmt.Sprintf(`<font color="%s"><b>%s</b></font>`, colors[item.Level], item.Level)
+		}
+		if !item.Timestamp.IsZero() {
+			if header != "" {
+				header += " "
+			}
+			header += item.Timestamp.UTC().Format(time.RFC3339)
+		}
+		if header != "" {
+			widgets = append(widgets, widget{TextParagraph: &textParagraph{Text: header}})
+		}
+
+		widgets = append(widgets, widget{TextParagraph: &textParagraph{Text: html.EscapeString(item.Text)}})
+
+		for _, field := range item.Fields {
+			widgets = append(widgets, widget{KeyValue: &keyValue{
+				TopLabel: field.Key,
+				Content:  html.EscapeString(field.Value),
+			}})
+		}
+
+		sections = append(sections, cardSection{Widgets: widgets})
+	}
+
+	c := card{Sections: sections}
+	if config.Title != "" {
+		c.Header = &cardHeader{Title: config.Title}
+	}
+
+	return JSON{
+		Cards: []card{c},
+	}
+}
@@ -1,8 +1,14 @@
 package googlechat
 
 import (
+	"encoding/json"
+	"net/http"
 	"net/url"
 	"testing"
+	"time"
+
+	"github.com/containrrr/shoutrrr/internal/testutils"
+	"github.com/containrrr/shoutrrr/pkg/types"
 
 	"github.com/jarcoal/httpmock"
 
@@ -33,6 +39,7 @@ var _ = Describe("Google Chat Service", func() {
 			Expect(err).NotTo(HaveOccurred(), "parsing")
 
 			config := &Config{}
+			testutils.TestConfigSetDefaultValues(config)
 			err = config.SetURL(url)
 			Expect(err).NotTo(HaveOccurred(), "verifying")
 
@@ -43,6 +50,47 @@ var _ = Describe("Google Chat Service", func() {
 		})
 	})
 
+	Describe("creating the payload from message items", func() {
+		It("should add a section for each item", func() {
+			items := []types.MessageItem{
+				{Text: "a < b", Level: types.Warning, Timestamp: time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC)},
+				{Text: "plain"},
+			}
+			items[0].WithField("disk", "95%")
+
+			config := &Config{}
+			testutils.TestConfigSetDefaultValues(config)
+			payload, err := json.Marshal(CreateJSONFromItems(config, items))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(payload).To(MatchJSON(`{"cards":[{"sections":[
+				{"widgets":[
+					{"textParagraph":{"text":"<font color=\"#ffc441\"><b>Warning</b></font> 2022-01-02T15:04:05Z"}},
+					{"textParagraph":{"text":"a &lt; b"}},
+					{"keyValue":{"topLabel":"disk","content":"95%"}}
+				]},
+				{"widgets":[{"textParagraph":{"text":"plain"}}]}
+			]}]}`))
+		})
+		It("should use the title and level colors of the config", func() {
+			config := &Config{}
+			testutils.TestConfigSetDefaultValues(config)
+			Expect(config.SetURL(testutils.URLMust("googlechat://chat.googleapis.com/v1/spaces/FOO/messages?key=bar&token=baz&title=Disk&colorWarn=orange"))).To(Succeed())
+
+			payload, err := json.Marshal(CreateJSONFromItems(config, []types.MessageItem{{Text: "full", Level: types.Warning}}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(payload).To(MatchJSON(`{"cards":[{"header":{"title":"Disk"},"sections":[
+				{"widgets":[
+					{"textParagraph":{"text":"<font color=\"orange\"><b>Warning</b></font>"}},
+					{"textParagraph":{"text":"full"}}
+				]}
+			]}]}`))
+		})
+		It("should implement the RichSender interface", func() {
+			var impl types.RichSender = &Service{}
+			Expect(impl).ToNot(BeNil())
+		})
+	})
+
 	Describe("sending the payload", func() {
 		var err error
 		BeforeEach(func() {
@@ -69,5 +117,25 @@ var _ = Describe("Google Chat Service", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		It("should apply the title param to message items", func() {
+			serviceURL := testutils.URLMust("googlechat://chat.googleapis.com/v1/spaces/FOO/messages?key=bar&token=baz")
+			service := Service{}
+			Expect(service.Initialize(serviceURL, nil)).To(Succeed())
+
+			var body JSON
+			httpmock.RegisterResponder("POST", "https://chat.googleapis.com/v1/spaces/FOO/messages?key=bar&token=baz",
+				func(req *http.Request) (*http.Response, error) {
+					Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+					return httpmock.NewStringResponse(200, ""), nil
+				})
+
+			err = service.SendItems([]types.MessageItem{{Text: "full"}}, &types.Params{"title": "Disk"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(body.Cards[0].Header).To(Equal(&cardHeader{Title: "Disk"}))
+
+			err = service.Send("full", &types.Params{"title": "Disk"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(body.Text).To(Equal("*Disk*\nfull"))
+		})
 	})
 })
@@ -1,6 +1,7 @@
 package gotify
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net/http"
@@ -21,6 +22,8 @@ type Service struct {
 	pkr        format.PropKeyResolver
 	httpClient *http.Client
 	client     jsonclient.Client
+	// customHTTPClient is the client set using SetHTTPClient, which is used instead of the default one if set
+	customHTTPClient *http.Client
 }
 
 // Initialize loads ServiceConfig from configURL and sets logger for this Service
@@ -32,23 +35,31 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 	service.pkr = format.NewPropKeyResolver(service.config)
 	err := service.config.SetURL(configURL)
 
-	service.httpClient = &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				// If DisableTLS is specified, we might still need to disable TLS verification
-				// since the default configuration of Gotify redirects HTTP to HTTPS
-				// Note that this cannot be overridden using params, only using the config URL
-				InsecureSkipVerify: service.config.DisableTLS,
-			},
-		},
-		// Set a reasonable timeout to prevent one bad transfer from block all subsequent ones
-		Timeout: 10 * time.Second,
-	}
-	service.client = jsonclient.NewWithHTTPClient(service.httpClient)
+	service.useHTTPClient(service.customHTTPClient)
 
 	return err
 }
 
+// useHTTPClient sets the client used for requests, creating the default one if httpClient is nil
+func (service *Service) useHTTPClient(httpClient *http.Client) {
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					// If DisableTLS is specified, we might still need to disable TLS verification
+					// since the default configuration of Gotify redirects HTTP to HTTPS
+					// Note that this cannot be overridden using params, only using the config URL
+					InsecureSkipVerify: service.config.DisableTLS,
+				},
+			},
+			// Set a reasonable timeout to prevent one bad transfer from block all subsequent ones
+			Timeout: 10 * time.Second,
+		}
+	}
+	service.httpClient = httpClient
+	service.client = jsonclient.NewWithHTTPClient(httpClient)
+}
+
 const tokenChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789.-_"
 
 // The validation rules have been taken directly from the Gotify source code.
@@ -82,15 +93,20 @@ func buildURL(config *Config) (string, error) {
 
 // Send a notification message to Gotify
 func (service *Service) Send(message string, params *types.Params) error {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to Gotify, aborting the request when ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
 	if params == nil {
 		params = &types.Params{}
 	}
-	config := service.config
-	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
-		service.Logf("Failed to update params: %v", err)
+	config := *service.config
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		service.LogAt(types.LogLevelWarn, "send", fmt.Sprintf("Failed to update params: %v", err), "Failed to update params", "error", err)
 	}
 
-	postURL, err := buildURL(config)
+	postURL, err := buildURL(&config)
 	if err != nil {
 		return err
 	}
@@ -101,19 +117,28 @@ func (service *Service) Send(message string, params *types.Params) error {
 		Priority: config.Priority,
 	}
 	response := &messageResponse{}
-	err = service.client.Post(postURL, request, response)
+	err = service.client.PostContext(ctx, postURL, request, response)
 	if err != nil {
 		errorRes := &errorResponse{}
 		if service.client.ErrorResponse(err, errorRes) {
 			return errorRes
 		}
-		return fmt.Errorf("failed to send notification to Gotify: %s", err)
+		return fmt.Errorf("failed to send notification to Gotify: %w", err)
 	}
 
 	return nil
 }
 
-// GetHTTPClient is only supposed to be used for mocking the httpclient when testing
+// GetHTTPClient returns the client used for HTTP requests
 func (service *Service) GetHTTPClient() *http.Client {
 	return service.httpClient
 }
+
+// SetHTTPClient replaces the client used for HTTP requests, passing nil restores the default client.
+// Note that the disabletls option does not affect the TLS verification of a replaced client.
+func (service *Service) SetHTTPClient(client *http.Client) {
+	service.customHTTPClient = client
+	if service.config != nil {
+		service.useHTTPClient(client)
+	}
+}
@@ -13,7 +13,7 @@ import (
 // Config for use within the gotify plugin
 type Config struct {
 	standard.EnumlessConfig
-	Token      string `url:"path2" desc:"Application token" required:""`
+	Token      string `url:"path2" desc:"Application token" required:"" secret:""`
 	Host       string `url:"host,port" desc:"Server hostname (and optionally port)" required:""`
 	Path       string `optional:"" url:"path1" desc:"Server subpath"`
 	Priority   int    `key:"priority" default:"0"`
@@ -2,6 +2,7 @@ package ifttt
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
 )
 
 const (
@@ -39,21 +41,26 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 
 // Send a notification message to a IFTTT webhook
 func (service *Service) Send(message string, params *types.Params) error {
-	config := service.config
-	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to a IFTTT webhook, aborting the request when ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	config := *service.config
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
 		return err
 	}
 
-	payload, err := createJSONToSend(config, message, params)
-	fmt.Printf("%+v", payload)
+	payload, err := createJSONToSend(&config, message, params)
 	if err != nil {
 		return err
 	}
+	service.LogAt(types.LogLevelDebug, "send", "", "Created payload", "payload", string(payload))
 	for _, event := range config.Events {
 		apiURL := service.createAPIURLForEvent(event)
-		err := doSend(payload, apiURL)
+		err := service.doSend(ctx, payload, apiURL)
 		if err != nil {
-			return fmt.Errorf("failed to send IFTTT event \"%s\": %s", event, err)
+			return fmt.Errorf("failed to send IFTTT event \"%s\": %w", event, err)
 		}
 	}
 	return nil
@@ -68,13 +75,19 @@ func (service *Service) createAPIURLForEvent(event string) string {
 	)
 }
 
-func doSend(payload []byte, postURL string) error {
-	res, err := http.Post(postURL, "application/json", bytes.NewBuffer(payload))
+func (service *Service) doSend(ctx context.Context, payload []byte, postURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, postURL, bytes.NewBuffer(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := service.GetHTTPClient().Do(req)
 	if err != nil {
 		return err
 	}
 	if res.StatusCode > 299 || res.StatusCode < 200 {
-		return fmt.Errorf("got response status code %s", res.Status)
+		return util.NewStatusError(res, fmt.Errorf("got response status code %s", res.Status))
 	}
 	return nil
 }
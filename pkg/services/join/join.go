@@ -1,6 +1,7 @@
 package join
 
 import (
+	"context"
 	"fmt"
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
 )
 
 const (
@@ -25,6 +27,11 @@ type Service struct {
 
 // Send a notification message to Pushover
 func (service *Service) Send(message string, params *types.Params) error {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to Join, aborting the request when ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
 	config := service.config
 	if params == nil {
 		params = &types.Params{}
@@ -42,10 +49,10 @@ func (service *Service) Send(message string, params *types.Params) error {
 
 	devices := strings.Join(config.Devices, ",")
 
-	return service.sendToDevices(devices, message, title, icon)
+	return service.sendToDevices(ctx, devices, message, title, icon)
 }
 
-func (service *Service) sendToDevices(devices string, message string, title string, icon string) error {
+func (service *Service) sendToDevices(ctx context.Context, devices string, message string, title string, icon string) error {
 	config := service.config
 
 	apiURL, err := url.Parse(hookURL)
@@ -68,17 +75,19 @@ func (service *Service) sendToDevices(devices string, message string, title stri
 
 	apiURL.RawQuery = data.Encode()
 
-	res, err := http.Post(
-		apiURL.String(),
-		contentType,
-		nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
 
+	res, err := service.GetHTTPClient().Do(req)
 	if err != nil {
 		return err
 	}
 
 	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to send notification to join devices %q, response status %q", devices, res.Status)
+		return util.NewStatusError(res, fmt.Errorf("failed to send notification to join devices %q, response status %q", devices, res.Status))
 	}
 
 	return nil
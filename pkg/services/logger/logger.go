@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strings"
@@ -17,6 +18,15 @@ type Service struct {
 
 // Send a notification message to log
 func (service *Service) Send(message string, params *types.Params) error {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to log, returning the context error if it is already done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	data := types.Params{}
 	if params != nil {
 		for key, value := range *params {
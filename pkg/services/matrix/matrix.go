@@ -1,10 +1,12 @@
 package matrix
 
 import (
+	"context"
 	"fmt"
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	t "github.com/containrrr/shoutrrr/pkg/types"
+	"net/http"
 	"net/url"
 )
 
@@ -29,9 +31,9 @@ func (s *Service) Initialize(configURL *url.URL, logger t.StdLogger) error {
 		return err
 	}
 
-	s.client = newClient(s.config.Host, s.config.DisableTLS, logger)
+	s.client = newClient(s.config.Host, s.config.DisableTLS, s.GetHTTPClient(), &s.Logger)
 	if s.config.User != "" {
-		return s.client.login(s.config.User, s.config.Password)
+		return s.client.login(context.Background(), s.config.User, s.config.Password)
 	}
 
 	s.client.useToken(s.config.Password)
@@ -40,19 +42,32 @@ func (s *Service) Initialize(configURL *url.URL, logger t.StdLogger) error {
 
 // Send notification
 func (s *Service) Send(message string, params *t.Params) error {
+	return s.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification, aborting any pending requests when ctx is done
+func (s *Service) SendContext(ctx context.Context, message string, params *t.Params) error {
 	config := *s.config
 	if err := s.pkr.UpdateConfigFromParams(&config, params); err != nil {
 		return err
 	}
 
-	errors := s.client.sendMessage(message, s.config.Rooms)
+	errors := s.client.sendMessage(ctx, message, s.config.Rooms)
 
 	if len(errors) > 0 {
 		for _, err := range errors {
-			s.Logf("error sending message: %w", err)
+			s.LogAt(t.LogLevelError, "send", fmt.Sprintf("error sending message: %v", err), "Error sending message", "error", err)
 		}
 		return fmt.Errorf("%v error(s) sending message, with initial error: %v", len(errors), errors[0])
 	}
 
 	return nil
 }
+
+// SetHTTPClient replaces the client used for HTTP requests, including the ones made by an already initialized service
+func (s *Service) SetHTTPClient(client *http.Client) {
+	s.HTTPClient.SetHTTPClient(client)
+	if s.client != nil {
+		s.client.httpClient = s.GetHTTPClient()
+	}
+}
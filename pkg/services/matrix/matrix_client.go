@@ -2,6 +2,7 @@ package matrix
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -9,34 +10,32 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
-	"github.com/containrrr/shoutrrr/pkg/util"
 )
 
 type client struct {
 	apiURL      url.URL
 	accessToken string
-	logger      types.StdLogger
+	logger      *standard.Logger
+	httpClient  *http.Client
 }
 
-func newClient(host string, disableTLS bool, logger types.StdLogger) (c *client) {
+func newClient(host string, disableTLS bool, httpClient *http.Client, logger *standard.Logger) (c *client) {
 	c = &client{
-		logger: logger,
+		logger:     logger,
+		httpClient: httpClient,
 		apiURL: url.URL{
 			Host:   host,
 			Scheme: "https",
 		},
 	}
 
-	if c.logger == nil {
-		c.logger = util.DiscardLogger
-	}
-
 	if disableTLS {
 		c.apiURL.Scheme = c.apiURL.Scheme[:4]
 	}
 
-	c.logger.Printf("Using server: %v\n", c.apiURL.String())
+	c.logger.LogAt(types.LogLevelDebug, "initialize", fmt.Sprintf("Using server: %v\n", c.apiURL.String()), "Using server", "server", c.apiURL.String())
 
 	return c
 }
@@ -46,12 +45,12 @@ func (c *client) useToken(token string) {
 	c.updateAccessToken()
 }
 
-func (c *client) login(user string, password string) error {
+func (c *client) login(ctx context.Context, user string, password string) error {
 	c.apiURL.RawQuery = ""
 	defer c.updateAccessToken()
 
 	resLogin := apiResLoginFlows{}
-	if err := c.apiGet(apiLogin, &resLogin); err != nil {
+	if err := c.apiGet(ctx, apiLogin, &resLogin); err != nil {
 		return fmt.Errorf("failed to get login flows: %w", err)
 	}
 
@@ -59,17 +58,17 @@ func (c *client) login(user string, password string) error {
 	for _, flow := range resLogin.Flows {
 		flows = append(flows, string(flow.Type))
 		if flow.Type == flowLoginPassword {
-			c.logf("Using login flow '%v'", flow.Type)
-			return c.loginPassword(user, password)
+			c.logger.LogAt(types.LogLevelDebug, "login", fmt.Sprintf("Using login flow '%v'", flow.Type), "Using login flow", "flow", flow.Type)
+			return c.loginPassword(ctx, user, password)
 		}
 	}
 
 	return fmt.Errorf("none of the server login flows are supported: %v", strings.Join(flows, ", "))
 }
 
-func (c *client) loginPassword(user string, password string) error {
+func (c *client) loginPassword(ctx context.Context, user string, password string) error {
 	response := apiResLogin{}
-	if err := c.apiPost(apiLogin, apiReqLogin{
+	if err := c.apiPost(ctx, apiLogin, apiReqLogin{
 		Type:       flowLoginPassword,
 		Password:   password,
 		Identifier: newUserIdentifier(user),
@@ -83,37 +82,40 @@ func (c *client) loginPassword(user string, password string) error {
 		tokenHint = response.AccessToken[:3]
 	}
 
-	c.logf("AccessToken: %v...\n", tokenHint)
-	c.logf("HomeServer: %v\n", response.HomeServer)
-	c.logf("User: %v\n", response.UserID)
+	stdMessage := fmt.Sprintf("AccessToken: %v...\nHomeServer: %v\nUser: %v\n", tokenHint, response.HomeServer, response.UserID)
+	c.logger.LogAt(types.LogLevelInfo, "login", stdMessage, "Logged in",
+		"tokenHint", tokenHint+"...",
+		"homeServer", response.HomeServer,
+		"user", response.UserID,
+	)
 
 	return nil
 }
 
-func (c *client) sendMessage(message string, rooms []string) (errors []error) {
+func (c *client) sendMessage(ctx context.Context, message string, rooms []string) (errors []error) {
 	if len(rooms) > 0 {
-		return c.sendToExplicitRooms(rooms, message)
+		return c.sendToExplicitRooms(ctx, rooms, message)
 	}
-	return c.sendToJoinedRooms(message)
+	return c.sendToJoinedRooms(ctx, message)
 }
 
-func (c *client) sendToExplicitRooms(rooms []string, message string) (errors []error) {
+func (c *client) sendToExplicitRooms(ctx context.Context, rooms []string, message string) (errors []error) {
 	var err error
 
 	for _, room := range rooms {
-		c.logf("Sending message to '%v'...\n", room)
+		c.logger.LogAt(types.LogLevelDebug, "send", fmt.Sprintf("Sending message to '%v'...\n", room), "Sending message", "room", room)
 
 		var roomID string
-		if roomID, err = c.joinRoom(room); err != nil {
+		if roomID, err = c.joinRoom(ctx, room); err != nil {
 			errors = append(errors, fmt.Errorf("error joining room %v: %w", roomID, err))
 			continue
 		}
 
 		if room != roomID {
-			c.logf("Resolved room alias '%v' to ID '%v'", room, roomID)
+			c.logger.LogAt(types.LogLevelDebug, "send", fmt.Sprintf("Resolved room alias '%v' to ID '%v'", room, roomID), "Resolved room alias", "alias", room, "room", roomID)
 		}
 
-		if err := c.sendMessageToRoom(message, roomID); err != nil {
+		if err := c.sendMessageToRoom(ctx, message, roomID); err != nil {
 			errors = append(errors, fmt.Errorf("failed to send message to room '%v': %w", roomID, err))
 		}
 	}
@@ -121,16 +123,16 @@ func (c *client) sendToExplicitRooms(rooms []string, message string) (errors []e
 	return errors
 }
 
-func (c *client) sendToJoinedRooms(message string) (errors []error) {
-	joinedRooms, err := c.getJoinedRooms()
+func (c *client) sendToJoinedRooms(ctx context.Context, message string) (errors []error) {
+	joinedRooms, err := c.getJoinedRooms(ctx)
 	if err != nil {
 		return append(errors, fmt.Errorf("failed to get joined rooms: %w", err))
 	}
 
 	// Send to all rooms that are joined
 	for _, roomID := range joinedRooms {
-		c.logf("Sending message to '%v'...\n", roomID)
-		if err := c.sendMessageToRoom(message, roomID); err != nil {
+		c.logger.LogAt(types.LogLevelDebug, "send", fmt.Sprintf("Sending message to '%v'...\n", roomID), "Sending message", "room", roomID)
+		if err := c.sendMessageToRoom(ctx, message, roomID); err != nil {
 			errors = append(errors, fmt.Errorf("failed to send message to room '%v': %w", roomID, err))
 		}
 	}
@@ -138,28 +140,32 @@ func (c *client) sendToJoinedRooms(message string) (errors []error) {
 	return errors
 }
 
-func (c *client) joinRoom(room string) (roomID string, err error) {
+func (c *client) joinRoom(ctx context.Context, room string) (roomID string, err error) {
 	resRoom := apiResRoom{}
-	if err = c.apiPost(fmt.Sprintf(apiRoomJoin, room), nil, &resRoom); err != nil {
+	if err = c.apiPost(ctx, fmt.Sprintf(apiRoomJoin, room), nil, &resRoom); err != nil {
 		return "", err
 	}
 	return resRoom.RoomID, nil
 }
 
-func (c *client) sendMessageToRoom(message string, roomID string) error {
+func (c *client) sendMessageToRoom(ctx context.Context, message string, roomID string) error {
 	resEvent := apiResEvent{}
-	return c.apiPost(fmt.Sprintf(apiSendMessage, roomID), apiReqSend{
+	return c.apiPost(ctx, fmt.Sprintf(apiSendMessage, roomID), apiReqSend{
 		MsgType: msgTypeText,
 		Body:    message,
 	}, &resEvent)
 }
 
-func (c *client) apiGet(path string, response interface{}) error {
+func (c *client) apiGet(ctx context.Context, path string, response interface{}) error {
 	c.apiURL.Path = path
 
-	var err error
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL.String(), nil)
+	if err != nil {
+		return err
+	}
+
 	var res *http.Response
-	res, err = http.Get(c.apiURL.String())
+	res, err = c.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -186,7 +192,7 @@ func (c *client) apiGet(path string, response interface{}) error {
 	return json.Unmarshal(body, response)
 }
 
-func (c *client) apiPost(path string, request interface{}, response interface{}) error {
+func (c *client) apiPost(ctx context.Context, path string, request interface{}, response interface{}) error {
 	c.apiURL.Path = path
 
 	var err error
@@ -197,8 +203,15 @@ func (c *client) apiPost(path string, request interface{}, response interface{})
 		return err
 	}
 
+	var req *http.Request
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
 	var res *http.Response
-	res, err = http.Post(c.apiURL.String(), contentType, bytes.NewReader(body))
+	res, err = c.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -230,13 +243,9 @@ func (c *client) updateAccessToken() {
 	c.apiURL.RawQuery = query.Encode()
 }
 
-func (c *client) logf(format string, v ...interface{}) {
-	c.logger.Printf(format, v...)
-}
-
-func (c *client) getJoinedRooms() ([]string, error) {
+func (c *client) getJoinedRooms(ctx context.Context) ([]string, error) {
 	response := apiResJoinedRooms{}
-	if err := c.apiGet(apiJoinedRooms, &response); err != nil {
+	if err := c.apiGet(ctx, apiJoinedRooms, &response); err != nil {
 		return []string{}, err
 	}
 	return response.Rooms, nil
@@ -2,6 +2,7 @@ package mattermost
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -10,6 +11,7 @@ import (
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
 )
 
 // Service sends notifications to a pre-configured channel or user
@@ -19,29 +21,80 @@ type Service struct {
 	pkr    format.PropKeyResolver
 }
 
+// limits are the message limits of the mattermost API, which allows posts of up to 16383 characters
+var limits = types.MessageLimit{
+	ChunkSize:      16383,
+	TotalChunkSize: 16383,
+	ChunkCount:     100,
+}
+
+// MessageLimit returns the payload limits of the mattermost API
+func (service *Service) MessageLimit() types.MessageLimit {
+	return limits
+}
+
 // Initialize loads ServiceConfig from configURL and sets logger for this Service
 func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) error {
 	service.Logger.SetLogger(logger)
 	service.config = &Config{}
 	service.pkr = format.NewPropKeyResolver(service.config)
+	if err := service.pkr.SetDefaultProps(service.config); err != nil {
+		return err
+	}
 	return service.config.setURL(&service.pkr, configURL)
 }
 
 // Send a notification message to Mattermost
 func (service *Service) Send(message string, params *types.Params) error {
-	config := service.config
-	apiURL := buildURL(config)
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to Mattermost, aborting the request when ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	config := *service.config
+	apiURL := buildURL(&config)
+
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return err
+	}
+	json, _ := CreateJSONPayload(&config, message, params)
+	return service.send(ctx, apiURL, json)
+}
 
-	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
+// SendItems sends items to Mattermost as attachments, colored by their level and with their fields
+func (service *Service) SendItems(items []types.MessageItem, params *types.Params) error {
+	return service.SendItemsContext(context.Background(), items, params)
+}
+
+// SendItemsContext sends items to Mattermost as attachments, colored by their level and with their fields,
+// aborting the request when ctx is done
+func (service *Service) SendItemsContext(ctx context.Context, items []types.MessageItem, params *types.Params) error {
+	config := *service.config
+	apiURL := buildURL(&config)
+
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return err
+	}
+	json, err := CreateJSONPayloadFromItems(&config, items, params)
+	if err != nil {
 		return err
 	}
-	json, _ := CreateJSONPayload(config, message, params)
-	res, err := http.Post(apiURL, "application/json", bytes.NewReader(json))
+	return service.send(ctx, apiURL, json)
+}
+
+func (service *Service) send(ctx context.Context, apiURL string, json []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(json))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := service.GetHTTPClient().Do(req)
 	if err != nil {
 		return err
 	}
 	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to send notification to service, response status code %s", res.Status)
+		return util.NewStatusError(res, fmt.Errorf("failed to send notification to service, response status code %s", res.Status))
 	}
 	return err
 }
@@ -18,7 +18,22 @@ type Config struct {
 	Title    string `key:"title" default:"" desc:"Notification title, optionally set by the sender (not used)"`
 	Channel  string `url:"path2" optional:"" desc:"Override webhook channel"`
 	Host     string `url:"host,port" desc:"Mattermost server host"`
-	Token    string `url:"path1" desc:"Webhook token"`
+	Token    string `url:"path1" desc:"Webhook token" secret:""`
+
+	ColorError string `key:"colorError" default:"#d60510" desc:"Attachment color for error message items"`
+	ColorWarn  string `key:"colorWarn" default:"#ffc441" desc:"Attachment color for warning message items"`
+	ColorInfo  string `key:"colorInfo" default:"#2488ff" desc:"Attachment color for info message items"`
+	ColorDebug string `key:"colorDebug" default:"#7b00ab" desc:"Attachment color for debug message items"`
+}
+
+// LevelColors returns an array of colors with a MessageLevel index
+func (config *Config) LevelColors() (colors [types.MessageLevelCount]string) {
+	colors[types.Error] = config.ColorError
+	colors[types.Warning] = config.ColorWarn
+	colors[types.Info] = config.ColorInfo
+	colors[types.Debug] = config.ColorDebug
+
+	return colors
 }
 
 // GetURL returns a URL representation of it's current field values
@@ -3,17 +3,34 @@ package mattermost
 import (
 	"encoding/json"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/containrrr/shoutrrr/pkg/types"
 )
 
 // JSON payload for mattermost notifications
 type JSON struct {
-	Text      string `json:"text"`
-	UserName  string `json:"username,omitempty"`
-	Channel   string `json:"channel,omitempty"`
-	IconEmoji string `json:"icon_emoji,omitempty"`
-	IconURL   string `json:"icon_url,omitempty"`
+	Text        string       `json:"text"`
+	UserName    string       `json:"username,omitempty"`
+	Channel     string       `json:"channel,omitempty"`
+	IconEmoji   string       `json:"icon_emoji,omitempty"`
+	IconURL     string       `json:"icon_url,omitempty"`
+	Attachments []attachment `json:"attachments,omitempty"`
+}
+
+type attachment struct {
+	Fallback string            `json:"fallback"`
+	Color    string            `json:"color,omitempty"`
+	Text     string            `json:"text"`
+	Fields   []attachmentField `json:"fields,omitempty"`
+	Footer   string            `json:"footer,omitempty"`
+}
+
+type attachmentField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
 }
 
 var iconURLPattern = regexp.MustCompile(`https?://`)
@@ -34,8 +51,50 @@ func (j *JSON) SetIcon(icon string) {
 
 // CreateJSONPayload for usage with the mattermost service
 func CreateJSONPayload(config *Config, message string, params *types.Params) ([]byte, error) {
+	payload := newPayload(config, params)
+	payload.Text = message
+
+	return json.Marshal(payload)
+}
+
+// CreateJSONPayloadFromItems creates a payload for usage with the mattermost service, using an attachment for each
+// item, colored by its level and with its fields
+func CreateJSONPayloadFromItems(config *Config, items []types.MessageItem, params *types.Params) ([]byte, error) {
+	payload := newPayload(config, params)
+	colors := config.LevelColors()
+
+	for _, item := range items {
+		att := attachment{
+			Fallback: item.Text,
+			Text:     item.Text,
+		}
+
+		var footer []string
+		if item.Level != types.Unknown && int(item.Level) < len(colors) {
+			att.Color = colors[item.Level]
+			footer = append(footer, item.Level.String())
+		}
+		if !item.Timestamp.IsZero() {
+			footer = append(footer, item.Timestamp.UTC().Format(time.RFC3339))
+		}
+		att.Footer = strings.Join(footer, " | ")
+
+		for _, field := range item.Fields {
+			att.Fields = append(att.Fields, attachmentField{
+				Title: field.Key,
+				Value: field.Value,
+				Short: true,
+			})
+		}
+
+		payload.Attachments = append(payload.Attachments, att)
+	}
+
+	return json.Marshal(payload)
+}
+
+func newPayload(config *Config, params *types.Params) JSON {
 	payload := JSON{
-		Text:     message,
 		UserName: config.UserName,
 		Channel:  config.Channel,
 	}
@@ -50,5 +109,5 @@ func CreateJSONPayload(config *Config, message string, params *types.Params) ([]
 	}
 	payload.SetIcon(config.Icon)
 
-	return json.Marshal(payload)
+	return payload
 }
@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/containrrr/shoutrrr/internal/testutils"
 	"github.com/containrrr/shoutrrr/pkg/types"
@@ -187,11 +188,48 @@ var _ = Describe("the mattermost service", func() {
 		})
 	})
 
+	Describe("sending message items", func() {
+		It("should generate an attachment for each item", func() {
+			config := &Config{}
+			testutils.TestConfigSetDefaultValues(config)
+			config.UserName = "bot"
+			items := []types.MessageItem{
+				{Text: "deployed", Level: types.Info, Timestamp: time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC)},
+				{Text: "plain"},
+			}
+			items[0].WithField("version", "1.2.3")
+
+			json, err := CreateJSONPayloadFromItems(config, items, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(json)).To(Equal(`{"text":"","username":"bot","attachments":[` +
+				`{"fallback":"deployed","color":"#2488ff","text":"deployed","fields":[{"title":"version","value":"1.2.3","short":true}],"footer":"Info | 2022-01-02T15:04:05Z"},` +
+				`{"fallback":"plain","text":"plain"}]}`))
+		})
+		It("should use the level colors of the service URL", func() {
+			config := &Config{}
+			testutils.TestConfigSetDefaultValues(config)
+			Expect(config.SetURL(testutils.URLMust("mattermost://mattermost.host/token?colorInfo=%23000000"))).To(Succeed())
+
+			json, err := CreateJSONPayloadFromItems(config, []types.MessageItem{
+				{Text: "deployed", Level: types.Info},
+				{Text: "failed", Level: types.Error},
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(json)).To(ContainSubstring(`"color":"#000000"`))
+			Expect(string(json)).To(ContainSubstring(`"color":"#d60510"`))
+		})
+		It("should implement the RichSender interface", func() {
+			var impl types.RichSender = &Service{}
+			Expect(impl).ToNot(BeNil())
+		})
+	})
+
 	When("parsing the configuration URL", func() {
 		It("should be identical after de-/serialization", func() {
 			input := "mattermost://bot@mattermost.host/token/channel"
 
 			config := &Config{}
+			testutils.TestConfigSetDefaultValues(config)
 			Expect(config.SetURL(testutils.URLMust(input))).To(Succeed())
 			Expect(config.GetURL().String()).To(Equal(input))
 		})
@@ -230,6 +268,7 @@ var _ = Describe("the mattermost service", func() {
 				Expect(err).NotTo(HaveOccurred(), "parsing")
 
 				config := &Config{}
+				testutils.TestConfigSetDefaultValues(config)
 				err = config.SetURL(url)
 				Expect(err).NotTo(HaveOccurred(), "verifying")
 
@@ -275,7 +314,7 @@ var _ = Describe("the mattermost service", func() {
 				testutils.TestConfigSetDefaultValues(&Config{})
 
 				testutils.TestConfigGetEnumsCount(&Config{}, 0)
-				testutils.TestConfigGetFieldsCount(&Config{}, 4)
+				testutils.TestConfigGetFieldsCount(&Config{}, 8)
 			})
 		})
 		Describe("the service instance", func() {
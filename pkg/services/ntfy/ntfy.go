@@ -2,6 +2,7 @@
 package ntfy
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -24,13 +25,18 @@ type Service struct {
 
 // Send a notification message to Ntfy
 func (service *Service) Send(message string, params *types.Params) error {
-	config := service.config
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to Ntfy, aborting the request when ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	config := *service.config
 
-	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
 		return err
 	}
 
-	if err := service.sendAPI(config, message); err != nil {
+	if err := service.sendAPI(ctx, &config, message); err != nil {
 		return fmt.Errorf("failed to send ntfy notification: %w", err)
 	}
 
@@ -49,10 +55,10 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 
 }
 
-func (service *Service) sendAPI(config *Config, message string) error {
+func (service *Service) sendAPI(ctx context.Context, config *Config, message string) error {
 	response := apiResponse{}
 	request := message
-	jsonClient := jsonclient.NewClient()
+	jsonClient := jsonclient.NewWithHTTPClient(service.GetHTTPClient())
 
 	headers := jsonClient.Headers()
 	headers.Del("Content-Type")
@@ -75,7 +81,7 @@ func (service *Service) sendAPI(config *Config, message string) error {
 		headers.Add("Firebase", "no")
 	}
 
-	if err := jsonClient.Post(config.GetAPIURL(), request, &response); err != nil {
+	if err := jsonClient.PostContext(ctx, config.GetAPIURL(), request, &response); err != nil {
 		if jsonClient.ErrorResponse(err, &response) {
 			// apiResponse implements Error
 			return &response
@@ -2,6 +2,7 @@ package opsgenie
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -11,6 +12,7 @@ import (
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
 )
 
 const (
@@ -24,7 +26,7 @@ type Service struct {
 	pkr    format.PropKeyResolver
 }
 
-func (service *Service) sendAlert(url string, apiKey string, payload AlertPayload) error {
+func (service *Service) sendAlert(ctx context.Context, url string, apiKey string, payload AlertPayload) error {
 	jsonBody, err := json.Marshal(payload)
 	if err != nil {
 		return err
@@ -32,24 +34,24 @@ func (service *Service) sendAlert(url string, apiKey string, payload AlertPayloa
 
 	jsonBuffer := bytes.NewBuffer(jsonBody)
 
-	req, err := http.NewRequest("POST", url, jsonBuffer)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, jsonBuffer)
 	if err != nil {
 		return err
 	}
 	req.Header.Add("Authorization", "GenieKey "+apiKey)
 	req.Header.Add("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := service.GetHTTPClient().Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send notification to OpsGenie: %s", err)
+		return fmt.Errorf("failed to send notification to OpsGenie: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, err := ioutil.ReadAll(resp.Body)
 		if err != nil {
-			return fmt.Errorf("OpsGenie notification returned %d HTTP status code. Cannot read body: %s", resp.StatusCode, err)
+			return util.NewStatusError(resp, fmt.Errorf("OpsGenie notification returned %d HTTP status code. Cannot read body: %w", resp.StatusCode, err))
 		}
-		return fmt.Errorf("OpsGenie notification returned %d HTTP status code: %s", resp.StatusCode, body)
+		return util.NewStatusError(resp, fmt.Errorf("OpsGenie notification returned %d HTTP status code: %s", resp.StatusCode, body))
 	}
 
 	return nil
@@ -66,13 +68,18 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 // Send a notification message to OpsGenie
 // See: https://docs.opsgenie.com/docs/alert-api#create-alert
 func (service *Service) Send(message string, params *types.Params) error {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to OpsGenie, aborting the request when ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
 	config := service.config
 	endpointURL := fmt.Sprintf(alertEndpointTemplate, config.Host, config.Port)
 	payload, err := service.newAlertPayload(message, params)
 	if err != nil {
 		return err
 	}
-	return service.sendAlert(endpointURL, config.APIKey, payload)
+	return service.sendAlert(ctx, endpointURL, config.APIKey, payload)
 }
 
 func (service *Service) newAlertPayload(message string, params *types.Params) (AlertPayload, error) {
@@ -13,7 +13,7 @@ const defaultPort = 443
 
 // Config for use within the opsgenie service
 type Config struct {
-	APIKey      string            `url:"path" desc:"The OpsGenie API key"`
+	APIKey      string            `url:"path" desc:"The OpsGenie API key" secret:""`
 	Host        string            `url:"host" desc:"The OpsGenie API host. Use 'api.eu.opsgenie.com' for EU instances" default:"api.opsgenie.com"`
 	Port        uint16            `url:"port" desc:"The OpsGenie API port." default:"443"`
 	Alias       string            `key:"alias" desc:"Client-defined identifier of the alert" optional:"true"`
@@ -1,6 +1,7 @@
 package pushbullet
 
 import (
+	"context"
 	"fmt"
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
@@ -16,7 +17,6 @@ const (
 // Service providing Pushbullet as a notification service
 type Service struct {
 	standard.Standard
-	client jsonclient.Client
 	config *Config
 	pkr    format.PropKeyResolver
 }
@@ -31,34 +31,39 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 		return err
 	}
 
-	service.client = jsonclient.NewClient()
-	service.client.Headers().Set("Access-Token", service.config.Token)
-
 	return nil
 }
 
 // Send a push notification via Pushbullet
 func (service *Service) Send(message string, params *types.Params) error {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a push notification via Pushbullet, aborting the request when ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
 	config := *service.config
 	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
 		return err
 	}
 
+	client := jsonclient.NewWithHTTPClient(service.GetHTTPClient())
+	client.Headers().Set("Access-Token", config.Token)
+
 	for _, target := range config.Targets {
-		if err := doSend(&config, target, message, service.client); err != nil {
+		if err := doSend(ctx, &config, target, message, client); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func doSend(config *Config, target string, message string, client jsonclient.Client) error {
+func doSend(ctx context.Context, config *Config, target string, message string, client jsonclient.Client) error {
 
 	push := NewNotePush(message, config.Title)
 	push.SetTarget(target)
 
 	response := PushResponse{}
-	if err := client.Post(pushesEndpoint, push, &response); err != nil {
+	if err := client.PostContext(ctx, pushesEndpoint, push, &response); err != nil {
 		errorResponse := &ErrorResponse{}
 		if client.ErrorResponse(err, errorResponse) {
 			return fmt.Errorf("API error: %w", errorResponse)
@@ -15,7 +15,7 @@ import (
 type Config struct {
 	standard.EnumlessConfig
 	Targets []string `url:"path"`
-	Token   string   `url:"host"`
+	Token   string   `url:"host" secret:""`
 	Title   string   `key:"title" default:"Shoutrrr notification"`
 }
 
@@ -1,6 +1,7 @@
 package pushover
 
 import (
+	"context"
 	"fmt"
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"net/http"
@@ -10,13 +11,21 @@ import (
 
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
 )
 
 const (
 	hookURL     = "https://api.pushover.net/1/messages.json"
 	contentType = "application/x-www-form-urlencoded"
+	maxlength   = 1024
 )
 
+var limits = types.MessageLimit{
+	ChunkSize:      maxlength,
+	TotalChunkSize: maxlength,
+	ChunkCount:     1,
+}
+
 // Service providing the notification service Pushover
 type Service struct {
 	standard.Standard
@@ -26,20 +35,30 @@ type Service struct {
 
 // Send a notification message to Pushover
 func (service *Service) Send(message string, params *types.Params) error {
-	config := service.config
-	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to Pushover, aborting the request when ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	config := *service.config
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
 		return err
 	}
 
 	device := strings.Join(config.Devices, ",")
-	if err := service.sendToDevice(device, message, config); err != nil {
+	if err := service.sendToDevice(ctx, device, message, &config); err != nil {
 		return fmt.Errorf("failed to send notifications to pushover devices: %w", err)
 	}
 
 	return nil
 }
 
-func (service *Service) sendToDevice(device string, message string, config *Config) error {
+// MessageLimit returns the payload limits of the pushover messages API
+func (service *Service) MessageLimit() types.MessageLimit {
+	return limits
+}
+
+func (service *Service) sendToDevice(ctx context.Context, device string, message string, config *Config) error {
 
 	data := url.Values{}
 	data.Set("device", device)
@@ -55,17 +74,19 @@ func (service *Service) sendToDevice(device string, message string, config *Conf
 		data.Set("priority", strconv.FormatInt(int64(config.Priority), 10))
 	}
 
-	res, err := http.Post(
-		hookURL,
-		contentType,
-		strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hookURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
 
+	res, err := service.GetHTTPClient().Do(req)
 	if err != nil {
 		return err
 	}
 
 	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to send notification to pushover device %q, response status %q", device, res.Status)
+		return util.NewStatusError(res, fmt.Errorf("failed to send notification to pushover device %q, response status %q", device, res.Status))
 	}
 
 	return nil
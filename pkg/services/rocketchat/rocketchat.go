@@ -2,6 +2,7 @@ package rocketchat
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
 )
 
 // Service sends notifications to a pre-configured channel or user
@@ -30,19 +32,30 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 
 // Send a notification message to Rocket.chat
 func (service *Service) Send(message string, params *types.Params) error {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to Rocket.chat, aborting the request when ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
 	var res *http.Response
 	var err error
 	config := service.config
 	apiURL := buildURL(config)
 	json, _ := CreateJSONPayload(config, message, params)
-	res, err = http.Post(apiURL, "application/json", bytes.NewReader(json))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(json))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err = service.GetHTTPClient().Do(req)
 	if err != nil {
 		return fmt.Errorf("Error while posting to URL: %w\nHOST: %s\nPORT: %s", err, config.Host, config.Port)
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusOK {
 		resBody, _ := ioutil.ReadAll(res.Body)
-		return fmt.Errorf("notification failed: %d %s", res.StatusCode, resBody)
+		return util.NewStatusError(res, fmt.Errorf("notification failed: %d %s", res.StatusCode, resBody))
 	}
 	return err
 }
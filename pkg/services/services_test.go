@@ -43,6 +43,7 @@ var serviceURLs = map[string]string{
 var serviceResponses = map[string]string{
 	"pushbullet": `{"created": 0}`,
 	"gotify":     `{"id": 0}`,
+	"telegram":   `{"ok": true, "result": {"message_id": 1}}`,
 }
 
 var logger = log.New(GinkgoWriter, "Test", log.LstdFlags)
@@ -103,4 +104,41 @@ var _ = Describe("services", func() {
 		}
 	})
 
+	When("the router has been given an HTTP client", func() {
+
+		AfterEach(func() {
+			httpmock.DeactivateAndReset()
+		})
+
+		for key, configURL := range serviceURLs {
+
+			key := key //necessary to ensure the correct value is passed to the closure
+			configURL := configURL
+
+			It("should be used for the requests made by "+key, func() {
+
+				if key == "smtp" || key == "xmpp" || key == "logger" {
+					Skip("does not use HTTP")
+				}
+
+				client := &http.Client{Transport: &http.Transport{}}
+				httpmock.ActivateNonDefault(client)
+				respStatus := http.StatusOK
+				if key == "discord" || key == "ifttt" {
+					respStatus = http.StatusNoContent
+				}
+				httpmock.RegisterNoResponder(httpmock.NewStringResponder(respStatus, serviceResponses[key]))
+
+				serviceRouter, err := router.New(logger)
+				Expect(err).NotTo(HaveOccurred())
+				serviceRouter.SetHTTPClient(client)
+				Expect(serviceRouter.AddService(configURL)).To(Succeed())
+
+				Expect(serviceRouter.Send("test", nil)).To(Equal([]error{nil}))
+				Expect(httpmock.GetTotalCallCount()).To(BeNumerically(">", 0))
+			})
+
+		}
+	})
+
 })
@@ -2,9 +2,11 @@ package slack
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/containrrr/shoutrrr/pkg/format"
+	"github.com/containrrr/shoutrrr/pkg/util"
 	"github.com/containrrr/shoutrrr/pkg/util/jsonclient"
 	"io/ioutil"
 	"net/http"
@@ -27,19 +29,43 @@ const (
 
 // Send a notification message to Slack
 func (service *Service) Send(message string, params *types.Params) error {
-	config := service.config
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to Slack, aborting the request when ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	config := *service.config
 
-	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
 		return err
 	}
 
-	payload := CreateJSONPayload(config, message)
+	return service.send(ctx, &config, CreateJSONPayload(&config, message))
+}
+
+// SendItems sends items to Slack as attachments, colored by their level and with their fields
+func (service *Service) SendItems(items []types.MessageItem, params *types.Params) error {
+	return service.SendItemsContext(context.Background(), items, params)
+}
+
+// SendItemsContext sends items to Slack as attachments, colored by their level and with their fields,
+// aborting the request when ctx is done
+func (service *Service) SendItemsContext(ctx context.Context, items []types.MessageItem, params *types.Params) error {
+	config := *service.config
+
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		return err
+	}
 
+	return service.send(ctx, &config, CreateJSONPayloadFromItems(&config, items))
+}
+
+func (service *Service) send(ctx context.Context, config *Config, payload interface{}) error {
 	var err error
 	if config.Token.IsAPIToken() {
-		err = service.sendAPI(config, payload)
+		err = service.sendAPI(ctx, config, payload)
 	} else {
-		err = service.sendWebhook(config, payload)
+		err = service.sendWebhook(ctx, config, payload)
 	}
 
 	if err != nil {
@@ -55,16 +81,20 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 	service.config = &Config{}
 	service.pkr = format.NewPropKeyResolver(service.config)
 
+	if err := service.pkr.SetDefaultProps(service.config); err != nil {
+		return err
+	}
+
 	return service.config.setURL(&service.pkr, configURL)
 
 }
 
-func (service *Service) sendAPI(config *Config, payload interface{}) error {
+func (service *Service) sendAPI(ctx context.Context, config *Config, payload interface{}) error {
 	response := APIResponse{}
-	jsonClient := jsonclient.NewClient()
+	jsonClient := jsonclient.NewWithHTTPClient(service.GetHTTPClient())
 	jsonClient.Headers().Set("Authorization", config.Token.Authorization())
 
-	if err := jsonClient.Post(apiPostMessage, payload, &response); err != nil {
+	if err := jsonClient.PostContext(ctx, apiPostMessage, payload, &response); err != nil {
 		return err
 	}
 
@@ -76,18 +106,24 @@ func (service *Service) sendAPI(config *Config, payload interface{}) error {
 	}
 
 	if response.Warning != "" {
-		service.Logger.Logf("Slack API warning: %q", response.Warning)
+		service.LogAt(types.LogLevelWarn, "send", fmt.Sprintf("Slack API warning: %q", response.Warning), "Slack API warning", "warning", response.Warning)
 	}
 
 	return nil
 }
 
-func (service *Service) sendWebhook(config *Config, payload interface{}) error {
+func (service *Service) sendWebhook(ctx context.Context, config *Config, payload interface{}) error {
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal payload: %w", err)
 	}
-	res, err := http.Post(config.Token.WebhookURL(), jsonclient.ContentType, bytes.NewBuffer(payloadBytes))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.Token.WebhookURL(), bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", jsonclient.ContentType)
+
+	res, err := service.GetHTTPClient().Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to invoke webhook: %w", err)
 	}
@@ -98,14 +134,14 @@ func (service *Service) sendWebhook(config *Config, payload interface{}) error {
 	switch response {
 	case "":
 		if res.StatusCode != http.StatusOK {
-			return fmt.Errorf("webhook status: %v", res.Status)
+			return util.NewStatusError(res, fmt.Errorf("webhook status: %v", res.Status))
 		}
 		// Treat status 200 as no error regardless of actual content
 		fallthrough
 	case "ok":
 		return nil
 	default:
-		return fmt.Errorf("webhook response: %v", response)
+		return util.NewStatusError(res, fmt.Errorf("webhook response: %v", response))
 	}
 
 }
@@ -11,13 +11,28 @@ import (
 // Config for the slack service
 type Config struct {
 	standard.EnumlessConfig
-	BotName string `optional:"uses bot default" key:"botname,username" desc:"Bot name"`
-	Icon    string `key:"icon,icon_emoji,icon_url" default:"" optional:"" desc:"Use emoji or URL as icon (based on presence of http(s):// prefix)"`
-	Token   Token  `desc:"API Bot token" url:"user,pass"`
-	Color   string `key:"color" optional:"default border color" desc:"Message left-hand border color"`
-	Title   string `key:"title" optional:"omitted" desc:"Prepended text above the message"`
-	Channel string `url:"host" desc:"Channel to send messages to in Cxxxxxxxxxx format"`
-	ThreadTS string   `key:"thread_ts" optional:"" desc:"ts value of the parent message (to send message as reply in thread)"`
+	BotName    string `optional:"uses bot default" key:"botname,username" desc:"Bot name"`
+	Icon       string `key:"icon,icon_emoji,icon_url" default:"" optional:"" desc:"Use emoji or URL as icon (based on presence of http(s):// prefix)"`
+	Token      Token  `desc:"API Bot token" url:"user,pass" secret:""`
+	Color      string `key:"color" optional:"default border color" desc:"Message left-hand border color"`
+	Title      string `key:"title" optional:"omitted" desc:"Prepended text above the message"`
+	Channel    string `url:"host" desc:"Channel to send messages to in Cxxxxxxxxxx format"`
+	ThreadTS   string `key:"thread_ts" optional:"" desc:"ts value of the parent message (to send message as reply in thread)"`
+	ColorError string `key:"colorError" default:"danger" desc:"Left-hand border color for error message items"`
+	ColorWarn  string `key:"colorWarn" default:"warning" desc:"Left-hand border color for warning message items"`
+	ColorInfo  string `key:"colorInfo" default:"#2488ff" desc:"Left-hand border color for info message items"`
+	ColorDebug string `key:"colorDebug" default:"#7b00ab" desc:"Left-hand border color for debug message items"`
+}
+
+// LevelColors returns an array of colors with a MessageLevel index
+func (config *Config) LevelColors() (colors [types.MessageLevelCount]string) {
+	colors[types.Unknown] = config.Color
+	colors[types.Error] = config.ColorError
+	colors[types.Warning] = config.ColorWarn
+	colors[types.Info] = config.ColorInfo
+	colors[types.Debug] = config.ColorDebug
+
+	return colors
 }
 
 // GetURL returns a URL representation of it's current field values
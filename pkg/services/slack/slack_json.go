@@ -3,6 +3,8 @@ package slack
 import (
 	"regexp"
 	"strings"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
 )
 
 // MessagePayload used within the Slack service
@@ -91,6 +93,50 @@ func CreateJSONPayload(config *Config, message string) interface{} {
 		atts = atts[:len(atts)-1]
 	}
 
+	return newMessagePayload(config, atts)
+}
+
+// CreateJSONPayloadFromItems creates a payload compatible with the slack post message API, using an attachment for
+// each item, colored by its level and with its fields and timestamp
+func CreateJSONPayloadFromItems(config *Config, items []types.MessageItem) interface{} {
+	colors := config.LevelColors()
+	var atts []attachment
+	for i, item := range items {
+		// Same as for plain messages, append the remaining items to the last attachment
+		if i >= 100 {
+			atts[len(atts)-1].Text += "\n" + item.Text
+			continue
+		}
+
+		att := attachment{
+			Text:  item.Text,
+			Color: config.Color,
+		}
+
+		if item.Level != types.Unknown && int(item.Level) < len(colors) {
+			att.Color = colors[item.Level]
+			att.Footer = item.Level.String()
+		}
+
+		if !item.Timestamp.IsZero() {
+			att.Time = int(item.Timestamp.Unix())
+		}
+
+		for _, field := range item.Fields {
+			att.Fields = append(att.Fields, legacyField{
+				Title: field.Key,
+				Value: field.Value,
+				Short: true,
+			})
+		}
+
+		atts = append(atts, att)
+	}
+
+	return newMessagePayload(config, atts)
+}
+
+func newMessagePayload(config *Config, atts []attachment) MessagePayload {
 	payload := MessagePayload{
 		ThreadTS:    config.ThreadTS,
 		Text:        config.Title,
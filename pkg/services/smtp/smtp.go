@@ -1,6 +1,7 @@
 package smtp
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"net/smtp"
 	"net/url"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/containrrr/shoutrrr/pkg/format"
@@ -23,6 +25,8 @@ type Service struct {
 	config            *Config
 	multipartBoundary string
 	propKeyResolver   format.PropKeyResolver
+	tlsConfig         *tls.Config
+	dialer            types.ContextDialer
 }
 
 const (
@@ -66,38 +70,92 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 
 // Send a notification message to e-mail recipients
 func (service *Service) Send(message string, params *types.Params) error {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to e-mail recipients, closing the connection when ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
 	config := service.config.Clone()
 	if err := service.propKeyResolver.UpdateConfigFromParams(&config, params); err != nil {
 		return fail(FailApplySendParams, err)
 	}
 
-	client, err := getClientConnection(service.config)
+	client, err := getClientConnection(ctx, service.config, service.getDialer(), service.getTLSConfig(service.config))
 	if err != nil {
 		return fail(FailGetSMTPClient, err)
 	}
 
+	// Abort the session if the context is done before the message has been sent
+	sent := make(chan struct{})
+	defer close(sent)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = client.Close()
+		case <-sent:
+		}
+	}()
+
 	return service.doSend(client, message, &config)
 }
 
-func getClientConnection(config *Config) (*smtp.Client, error) {
+// SetTLSConfig sets the config used for implicit TLS and StartTLS, passing nil restores the default config.
+// The server name is set to the host of the service URL, unless it has been set in config.
+func (service *Service) SetTLSConfig(config *tls.Config) {
+	service.tlsConfig = config
+}
 
-	var conn net.Conn
-	var err error
+func (service *Service) getTLSConfig(config *Config) *tls.Config {
+	if service.tlsConfig == nil {
+		return &tls.Config{
+			ServerName: config.Host,
+		}
+	}
 
-	addr := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	tlsConfig := service.tlsConfig.Clone()
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = config.Host
+	}
+	return tlsConfig
+}
 
-	if useImplicitTLS(config.Encryption, config.Port) {
-		conn, err = tls.Dial("tcp", addr, &tls.Config{
-			ServerName: config.Host,
-		})
-	} else {
-		conn, err = net.Dial("tcp", addr)
+// SetDialer sets the dialer used for connecting to the server, e.g. to connect using a proxy.
+// Passing nil restores the default dialer.
+func (service *Service) SetDialer(dialer types.ContextDialer) {
+	service.dialer = dialer
+}
+
+func (service *Service) getDialer() types.ContextDialer {
+	if service.dialer == nil {
+		return &net.Dialer{}
 	}
+	return service.dialer
+}
 
+func getClientConnection(ctx context.Context, config *Config, dialer types.ContextDialer, tlsConfig *tls.Config) (*smtp.Client, error) {
+
+	addr := net.JoinHostPort(config.Host, strconv.Itoa(int(config.Port)))
+
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
 	if err != nil {
 		return nil, fail(FailConnectToServer, err)
 	}
 
+	if useImplicitTLS(config.Encryption, config.Port) {
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err = tlsConn.HandshakeContext(ctx); err != nil {
+			_ = conn.Close()
+			return nil, fail(FailConnectToServer, err)
+		}
+		conn = tlsConn
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = conn.SetDeadline(deadline); err != nil {
+			return nil, fail(FailConnectToServer, err)
+		}
+	}
+
 	client, err := smtp.NewClient(conn, config.Host)
 	if err != nil {
 		return nil, fail(FailCreateSMTPClient, err)
@@ -122,11 +180,11 @@ func (service *Service) doSend(client *smtp.Client, message string, config *Conf
 
 	if config.UseStartTLS && !useImplicitTLS(config.Encryption, config.Port) {
 		if supported, _ := client.Extension("StartTLS"); !supported {
-			service.Logf("Warning: StartTLS enabled, but server did not report support for it. Connection is NOT encrypted")
+			service.LogAt(types.LogLevelWarn, "send",
+				"Warning: StartTLS enabled, but server did not report support for it. Connection is NOT encrypted",
+				"StartTLS enabled, but server did not report support for it. Connection is NOT encrypted")
 		} else {
-			if err := client.StartTLS(&tls.Config{
-				ServerName: config.Host,
-			}); err != nil {
+			if err := client.StartTLS(service.getTLSConfig(config)); err != nil {
 				return fail(FailEnableStartTLS, err)
 			}
 		}
@@ -147,7 +205,7 @@ func (service *Service) doSend(client *smtp.Client, message string, config *Conf
 			return fail(FailSendRecipient, err)
 		}
 
-		service.Logf("Mail successfully sent to \"%s\"!\n", toAddress)
+		service.LogAt(types.LogLevelInfo, "send", fmt.Sprintf("Mail successfully sent to \"%s\"!\n", toAddress), "Mail successfully sent", "recipient", toAddress)
 	}
 
 	// Send the QUIT command and close the connection.
@@ -166,7 +224,7 @@ func (service *Service) resolveClientHost(config *Config) string {
 
 	hostname, err := os.Hostname()
 	if err != nil {
-		service.Logf("Failed to get hostname, falling back to localhost: %v", err)
+		service.LogAt(types.LogLevelWarn, "send", fmt.Sprintf("Failed to get hostname, falling back to localhost: %v", err), "Failed to get hostname, falling back to localhost", "error", err)
 		return "localhost"
 	}
 
@@ -1,7 +1,11 @@
 package smtp
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"log"
+	"net"
 	"net/smtp"
 	"net/url"
 	"os"
@@ -115,6 +119,35 @@ var _ = Describe("the SMTP service", func() {
 
 		})
 	})
+	When("getting the TLS config", func() {
+		config := &Config{Host: "mail.example.com"}
+		It("should use the host as the server name by default", func() {
+			service := &Service{}
+			Expect(service.getTLSConfig(config).ServerName).To(Equal("mail.example.com"))
+		})
+		It("should use the config that has been set", func() {
+			service := &Service{}
+			service.SetTLSConfig(&tls.Config{InsecureSkipVerify: true})
+			tlsConfig := service.getTLSConfig(config)
+			Expect(tlsConfig.InsecureSkipVerify).To(BeTrue())
+			Expect(tlsConfig.ServerName).To(Equal("mail.example.com"))
+
+			service.SetTLSConfig(&tls.Config{ServerName: "smtp.example.com"})
+			Expect(service.getTLSConfig(config).ServerName).To(Equal("smtp.example.com"))
+		})
+	})
+	When("a dialer has been set", func() {
+		It("should be used to connect to the server", func() {
+			dialer := &failingDialer{}
+			service := &Service{}
+			service.SetDialer(dialer)
+
+			config := &Config{Host: "mail.example.com", Port: 465}
+			_, err := getClientConnection(context.Background(), config, service.getDialer(), service.getTLSConfig(config))
+			Expect(err).To(HaveOccurred())
+			Expect(dialer.addresses).To(Equal([]string{"mail.example.com:465"}))
+		})
+	})
 	When("sending a message", func() {
 		When("the service is not configured correctly", func() {
 			It("should fail to send messages", func() {
@@ -576,3 +609,13 @@ func fakeTLSEnabled(client *smtp.Client, hostname string) {
 func matchFailure(id failures.FailureID) gt.GomegaMatcher {
 	return MatchError(fail(id, nil))
 }
+
+// failingDialer records the addresses it is asked to connect to, and fails every connection
+type failingDialer struct {
+	addresses []string
+}
+
+func (fd *failingDialer) DialContext(_ context.Context, _ string, address string) (net.Conn, error) {
+	fd.addresses = append(fd.addresses, address)
+	return nil, errors.New("connection refused")
+}
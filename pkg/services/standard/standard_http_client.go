@@ -0,0 +1,22 @@
+package standard
+
+import "net/http"
+
+// HTTPClient provides the http.Client used by the service for making requests, which can be replaced to use custom
+// transports, proxies or instrumentation without changing http.DefaultClient
+type HTTPClient struct {
+	httpClient *http.Client
+}
+
+// GetHTTPClient returns the client used for HTTP requests, which is http.DefaultClient unless it has been replaced
+func (hc *HTTPClient) GetHTTPClient() *http.Client {
+	if hc.httpClient == nil {
+		return http.DefaultClient
+	}
+	return hc.httpClient
+}
+
+// SetHTTPClient replaces the client used for HTTP requests, passing nil restores the default client
+func (hc *HTTPClient) SetHTTPClient(client *http.Client) {
+	hc.httpClient = client
+}
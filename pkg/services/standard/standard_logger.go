@@ -1,23 +1,49 @@
 package standard
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/containrrr/shoutrrr/pkg/types"
 	"github.com/containrrr/shoutrrr/pkg/util"
 )
 
-// Logger provides the utility methods Log* that maps to Logger.Print*
+// Logger provides the utility methods Log* that maps to Logger.Print*, or to the structured logger if one is set
 type Logger struct {
-	logger types.StdLogger
+	logger     types.StdLogger
+	structured types.StructuredLogger
 }
 
 // Logf maps to the service loggers Logger.Printf function
 func (sl *Logger) Logf(format string, v ...interface{}) {
-	sl.logger.Printf(format, v...)
+	if sl.structured != nil {
+		sl.structured.Log(context.Background(), types.LogLevelInfo, fmt.Sprintf(format, v...))
+		return
+	}
+	sl.stdLogger().Printf(format, v...)
 }
 
 // Log maps to the service loggers Logger.Print function
 func (sl *Logger) Log(v ...interface{}) {
-	sl.logger.Print(v...)
+	if sl.structured != nil {
+		sl.structured.Log(context.Background(), types.LogLevelInfo, fmt.Sprint(v...))
+		return
+	}
+	sl.stdLogger().Print(v...)
+}
+
+// LogAt writes a record with the level and message to the structured logger, attaching the operation and the
+// attributes given as alternating keys and values. Without a structured logger, stdMessage is written to the std
+// logger instead, in the format that has always been used, or nothing if it is empty.
+func (sl *Logger) LogAt(level types.LogLevel, operation string, stdMessage string, msg string, keyvals ...interface{}) {
+	if sl.structured == nil {
+		if stdMessage != "" {
+			sl.stdLogger().Print(stdMessage)
+		}
+		return
+	}
+	keyvals = append([]interface{}{"operation", operation}, keyvals...)
+	sl.structured.Log(context.Background(), level, msg, keyvals...)
 }
 
 // SetLogger maps the specified logger to the Log* helper methods
@@ -28,3 +54,16 @@ func (sl *Logger) SetLogger(logger types.StdLogger) {
 		sl.logger = logger
 	}
 }
+
+// SetStructuredLogger sets the logger used by the Log* helper methods instead of the std logger, passing nil restores
+// the use of the std logger
+func (sl *Logger) SetStructuredLogger(logger types.StructuredLogger) {
+	sl.structured = logger
+}
+
+func (sl *Logger) stdLogger() types.StdLogger {
+	if sl.logger == nil {
+		return util.DiscardLogger
+	}
+	return sl.logger
+}
@@ -1,14 +1,19 @@
 package standard
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"net/http"
 	"os"
 	"strings"
 	"testing"
 
+	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -60,9 +65,53 @@ var _ = Describe("the standard logging implementation", func() {
 				Expect(builder.String()).To(Equal("foo 7\n"))
 			})
 		})
+		When("when  logger.LogAt is called", func() {
+			It("should log the std message", func() {
+
+				logger.SetLogger(stringLogger)
+				logger.LogAt(types.LogLevelWarn, "send", "Retrying attempt 2", "retrying", "attempt", 2)
+
+				Expect(builder.String()).To(Equal("Retrying attempt 2\n"))
+			})
+			It("should not log anything if the std message is empty", func() {
+
+				logger.SetLogger(stringLogger)
+				logger.LogAt(types.LogLevelDebug, "send", "", "payload", "body", "{}")
+
+				Expect(builder.String()).To(BeEmpty())
+			})
+		})
+	})
+	When("setstructuredlogger is called with a logger", func() {
+		var records []string
+		BeforeEach(func() {
+			records = nil
+			logger = &Logger{}
+			logger.SetLogger(log.New(io.Discard, "", 0))
+			logger.SetStructuredLogger(structuredLoggerFunc(func(level types.LogLevel, msg string, keyvals ...interface{}) {
+				records = append(records, level.String()+" "+util.FormatLogRecord(msg, keyvals...))
+			}))
+		})
+		It("should write the Log and Logf messages to it at the info level", func() {
+			logger.Log("foo")
+			logger.Logf("bar %d", 7)
+
+			Expect(records).To(Equal([]string{"INFO foo", "INFO bar 7"}))
+		})
+		It("should write the LogAt records to it with the operation and attributes", func() {
+			logger.LogAt(types.LogLevelError, "login", "Login failed for bot", "failed", "user", "bot")
+
+			Expect(records).To(Equal([]string{"ERROR failed operation=login user=bot"}))
+		})
 	})
 })
 
+type structuredLoggerFunc func(level types.LogLevel, msg string, keyvals ...interface{})
+
+func (f structuredLoggerFunc) Log(_ context.Context, level types.LogLevel, msg string, keyvals ...interface{}) {
+	f(level, msg, keyvals...)
+}
+
 var _ = Describe("the standard template implementation", func() {
 	When("a template is being set from a file", func() {
 		It("should load the template without any errors", func() {
@@ -131,3 +180,22 @@ var _ = Describe("the standard enumless config implementation", func() {
 		})
 	})
 })
+
+var _ = Describe("the standard http client implementation", func() {
+	When("no client has been set", func() {
+		It("should return the default client", func() {
+			Expect((&HTTPClient{}).GetHTTPClient()).To(BeIdenticalTo(http.DefaultClient))
+		})
+	})
+	When("a client has been set", func() {
+		It("should return that client until it is reset", func() {
+			hc := &HTTPClient{}
+			client := &http.Client{}
+			hc.SetHTTPClient(client)
+			Expect(hc.GetHTTPClient()).To(BeIdenticalTo(client))
+
+			hc.SetHTTPClient(nil)
+			Expect(hc.GetHTTPClient()).To(BeIdenticalTo(http.DefaultClient))
+		})
+	})
+})
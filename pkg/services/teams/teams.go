@@ -2,11 +2,13 @@ package teams
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
@@ -23,23 +25,29 @@ type Service struct {
 
 // Send a notification message to Microsoft Teams
 func (service *Service) Send(message string, params *types.Params) error {
-	config := service.config
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to Microsoft Teams, aborting the request when ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	config := *service.config
 
-	if err := service.pkr.UpdateConfigFromParams(config, params); err != nil {
-		service.Logf("Failed to update params: %v", err)
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		service.LogAt(types.LogLevelWarn, "send", fmt.Sprintf("Failed to update params: %v", err), "Failed to update params", "error", err)
 	}
 
-	return service.doSend(config, message)
+	return service.doSend(ctx, &config, message)
 }
 
 // Initialize loads ServiceConfig from configURL and sets logger for this Service
 func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) error {
 	service.Logger.SetLogger(logger)
-	service.config = &Config{
-		Host: LegacyHost,
-	}
-
+	service.config = &Config{}
 	service.pkr = format.NewPropKeyResolver(service.config)
+	if err := service.pkr.SetDefaultProps(service.config); err != nil {
+		return err
+	}
+	service.config.Host = LegacyHost
 
 	return service.config.setURL(&service.pkr, configURL)
 }
@@ -61,7 +69,58 @@ func (*Service) GetConfigURLFromCustom(customURL *url.URL) (serviceURL *url.URL,
 	return config.getURL(&resolver), nil
 }
 
-func (service *Service) doSend(config *Config, message string) error {
+// SendItems sends items to Microsoft Teams as sections of a card, with their fields as facts
+func (service *Service) SendItems(items []types.MessageItem, params *types.Params) error {
+	return service.SendItemsContext(context.Background(), items, params)
+}
+
+// SendItemsContext sends items to Microsoft Teams as sections of a card, with their fields as facts,
+// aborting the request when ctx is done
+func (service *Service) SendItemsContext(ctx context.Context, items []types.MessageItem, params *types.Params) error {
+	config := *service.config
+
+	if err := service.pkr.UpdateConfigFromParams(&config, params); err != nil {
+		service.LogAt(types.LogLevelWarn, "send", fmt.Sprintf("Failed to update params: %v", err), "Failed to update params", "error", err)
+	}
+
+	sections := make([]section, 0, len(items))
+	colors := config.LevelColors()
+	color := config.Color
+	maxLevel := types.Unknown
+
+	for _, item := range items {
+		sec := section{
+			Text: item.Text,
+		}
+
+		if item.Level != types.Unknown {
+			sec.ActivityTitle = item.Level.String()
+		}
+
+		if !item.Timestamp.IsZero() {
+			sec.ActivitySubtitle = item.Timestamp.UTC().Format(time.RFC3339)
+		}
+
+		for _, field := range item.Fields {
+			sec.Facts = append(sec.Facts, fact{
+				Key:   field.Key,
+				Value: field.Value,
+			})
+		}
+
+		// The card can only have a single color, so use the one of the most severe item
+		if item.Level > maxLevel && int(item.Level) < len(colors) {
+			maxLevel = item.Level
+			color = colors[item.Level]
+		}
+
+		sections = append(sections, sec)
+	}
+
+	return service.sendSections(ctx, &config, sections, color)
+}
+
+func (service *Service) doSend(ctx context.Context, config *Config, message string) error {
 	var sections []section
 
 	for _, line := range strings.Split(message, "\n") {
@@ -70,6 +129,10 @@ func (service *Service) doSend(config *Config, message string) error {
 		})
 	}
 
+	return service.sendSections(ctx, config, sections, config.Color)
+}
+
+func (service *Service) sendSections(ctx context.Context, config *Config, sections []section, color string) error {
 	// Teams need a summary for the webhook, use title or first (truncated) row
 	summary := config.Title
 	if summary == "" && len(sections) > 0 {
@@ -84,7 +147,7 @@ func (service *Service) doSend(config *Config, message string) error {
 		Context:    "http://schema.org/extensions",
 		Markdown:   true,
 		Title:      config.Title,
-		ThemeColor: config.Color,
+		ThemeColor: color,
 		Summary:    summary,
 		Sections:   sections,
 	})
@@ -97,18 +160,26 @@ func (service *Service) doSend(config *Config, message string) error {
 		host = LegacyHost
 		// Emit a warning to the log for now.
 		// TODO(v0.6): Remove legacy support as it should be fully deprecated now
-		service.Logf(`Warning: No host specified, update your Teams URL: %s`, util.DocsURL(`services/teams`))
+		docsURL := util.DocsURL(`services/teams`)
+		service.LogAt(types.LogLevelWarn, "send", `Warning: No host specified, update your Teams URL: `+docsURL,
+			"No host specified, update your Teams URL", "docs", docsURL)
 	}
 	postURL := buildWebhookURL(host, config.Group, config.Tenant, config.AltID, config.GroupOwner)
 
-	res, err := http.Post(postURL, "application/json", bytes.NewBuffer(payload))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, postURL, bytes.NewBuffer(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := service.GetHTTPClient().Do(req)
 	if err == nil && res.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to send notification to teams, response status code %s", res.Status)
+		return util.NewStatusError(res, fmt.Errorf("failed to send notification to teams, response status code %s", res.Status))
 	}
 	if err != nil {
 		return fmt.Errorf(
-			"an error occurred while sending notification to teams: %s",
-			err.Error(),
+			"an error occurred while sending notification to teams: %w",
+			err,
 		)
 	}
 	return nil
@@ -16,12 +16,27 @@ type Config struct {
 	standard.EnumlessConfig
 	Group      string `url:"user" optional:""`
 	Tenant     string `url:"host" optional:""`
-	AltID      string `url:"path1" optional:""`
+	AltID      string `url:"path1" optional:"" secret:""`
 	GroupOwner string `url:"path2" optional:""`
 
-	Title string `key:"title" optional:""`
-	Color string `key:"color" optional:""`
-	Host  string `key:"host" optional:"" default:"outlook.office.com"`
+	Title      string `key:"title" optional:""`
+	Color      string `key:"color" optional:""`
+	ColorError string `key:"colorError" default:"d60510" desc:"Card color for error message items"`
+	ColorWarn  string `key:"colorWarn" default:"ffc441" desc:"Card color for warning message items"`
+	ColorInfo  string `key:"colorInfo" default:"2488ff" desc:"Card color for info message items"`
+	ColorDebug string `key:"colorDebug" default:"7b00ab" desc:"Card color for debug message items"`
+	Host       string `key:"host" optional:"" default:"outlook.office.com"`
+}
+
+// LevelColors returns an array of colors with a MessageLevel index
+func (config *Config) LevelColors() (colors [types.MessageLevelCount]string) {
+	colors[types.Unknown] = config.Color
+	colors[types.Error] = config.ColorError
+	colors[types.Warning] = config.ColorWarn
+	colors[types.Info] = config.ColorInfo
+	colors[types.Debug] = config.ColorDebug
+
+	return colors
 }
 
 func (config *Config) webhookParts() [4]string {
@@ -41,9 +56,12 @@ func (config *Config) SetFromWebhookURL(webhookURL string) error {
 
 // ConfigFromWebhookURL creates a new Config from a parsed Teams Webhook URL
 func ConfigFromWebhookURL(webhookURL url.URL) (*Config, error) {
-	config := &Config{
-		Host: webhookURL.Host,
+	config := &Config{}
+	pkr := format.NewPropKeyResolver(config)
+	if err := pkr.SetDefaultProps(config); err != nil {
+		return nil, err
 	}
+	config.Host = webhookURL.Host
 
 	if err := config.SetFromWebhookURL(webhookURL.String()); err != nil {
 		return nil, err
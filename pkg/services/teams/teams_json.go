@@ -13,10 +13,12 @@ type payload struct {
 }
 
 type section struct {
-	Text         string `json:"text,omitempty"`
-	ActivityText string `json:"activityText,omitempty"`
-	StartGroup   bool   `json:"startGroup"`
-	Facts        []fact `json:"facts,omitempty"`
+	Text             string `json:"text,omitempty"`
+	ActivityTitle    string `json:"activityTitle,omitempty"`
+	ActivitySubtitle string `json:"activitySubtitle,omitempty"`
+	ActivityText     string `json:"activityText,omitempty"`
+	StartGroup       bool   `json:"startGroup"`
+	Facts            []fact `json:"facts,omitempty"`
 }
 
 type fact struct {
@@ -1,10 +1,16 @@
 package teams
 
 import (
+	"encoding/json"
 	"errors"
 	"log"
+	"net/http"
 	"net/url"
 	"testing"
+	"time"
+
+	"github.com/containrrr/shoutrrr/internal/testutils"
+	"github.com/containrrr/shoutrrr/pkg/types"
 
 	"github.com/jarcoal/httpmock"
 
@@ -69,7 +75,8 @@ var _ = Describe("the teams service", func() {
 				url, err := url.Parse(testURL)
 				Expect(err).NotTo(HaveOccurred(), "parsing")
 
-				config := &Config{Host: LegacyHost}
+				config := &Config{}
+				testutils.TestConfigSetDefaultValues(config)
 				err = config.SetURL(url)
 				Expect(err).NotTo(HaveOccurred(), "verifying")
 
@@ -139,6 +146,46 @@ var _ = Describe("the teams service", func() {
 			err = service.Send("Message", nil)
 			Expect(err).NotTo(HaveOccurred())
 		})
+		It("should send message items as sections, colored by the most severe level", func() {
+			serviceURL, _ := url.Parse(scopedURLBase)
+			err = service.Initialize(serviceURL, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			var body payload
+			httpmock.RegisterResponder("POST", scopedWebhookURL, func(req *http.Request) (*http.Response, error) {
+				Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+				return httpmock.NewStringResponse(200, ""), nil
+			})
+
+			items := []types.MessageItem{
+				{Text: "deploying", Level: types.Info},
+				{Text: "failed", Level: types.Error, Timestamp: time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC)},
+			}
+			items[1].WithField("host", "web1")
+
+			err = service.SendItems(items, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(body.ThemeColor).To(Equal("d60510"))
+			Expect(body.Sections).To(Equal([]section{
+				{Text: "deploying", ActivityTitle: "Info"},
+				{Text: "failed", ActivityTitle: "Error", ActivitySubtitle: "2022-01-02T15:04:05Z", Facts: []fact{{Key: "host", Value: "web1"}}},
+			}))
+		})
+		It("should use the level colors of the service URL", func() {
+			serviceURL, _ := url.Parse(scopedURLBase + "&colorError=ff0000")
+			err = service.Initialize(serviceURL, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			var body payload
+			httpmock.RegisterResponder("POST", scopedWebhookURL, func(req *http.Request) (*http.Response, error) {
+				Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+				return httpmock.NewStringResponse(200, ""), nil
+			})
+
+			err = service.SendItems([]types.MessageItem{{Text: "failed", Level: types.Error}}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(body.ThemeColor).To(Equal("ff0000"))
+		})
 		It("should not panic if an error occurs when sending the payload", func() {
 			serviceURL, _ := url.Parse(testURLBase)
 			err = service.Initialize(serviceURL, logger)
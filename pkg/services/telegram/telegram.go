@@ -1,9 +1,11 @@
 package telegram
 
 import (
+	"context"
 	"errors"
 	"github.com/containrrr/shoutrrr/pkg/format"
 	"net/url"
+	"unicode/utf8"
 
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
@@ -14,6 +16,12 @@ const (
 	maxlength = 4096
 )
 
+var limits = types.MessageLimit{
+	ChunkSize:      maxlength,
+	TotalChunkSize: maxlength,
+	ChunkCount:     1,
+}
+
 // Service sends notifications to a given telegram chat
 type Service struct {
 	standard.Standard
@@ -23,7 +31,12 @@ type Service struct {
 
 // Send notification to Telegram
 func (service *Service) Send(message string, params *types.Params) error {
-	if len(message) > maxlength {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification to Telegram, aborting the request when ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
+	if utf8.RuneCountInString(message) > maxlength {
 		return errors.New("Message exceeds the max length")
 	}
 
@@ -32,7 +45,12 @@ func (service *Service) Send(message string, params *types.Params) error {
 		return err
 	}
 
-	return service.sendMessageForChatIDs(message, &config)
+	return service.sendMessageForChatIDs(ctx, message, &config)
+}
+
+// MessageLimit returns the payload limits of the telegram bot API
+func (service *Service) MessageLimit() types.MessageLimit {
+	return limits
 }
 
 // Initialize loads ServiceConfig from configURL and sets logger for this Service
@@ -50,9 +68,9 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 	return nil
 }
 
-func (service *Service) sendMessageForChatIDs(message string, config *Config) error {
+func (service *Service) sendMessageForChatIDs(ctx context.Context, message string, config *Config) error {
 	for _, chat := range service.config.Chats {
-		if err := sendMessageToAPI(message, chat, config); err != nil {
+		if err := service.sendMessageToAPI(ctx, message, chat, config); err != nil {
 			return err
 		}
 	}
@@ -64,9 +82,9 @@ func (service *Service) GetConfig() *Config {
 	return service.config
 }
 
-func sendMessageToAPI(message string, chat string, config *Config) error {
-	client := &Client{token: config.Token}
+func (service *Service) sendMessageToAPI(ctx context.Context, message string, chat string, config *Config) error {
+	client := &Client{token: config.Token, httpClient: service.GetHTTPClient()}
 	payload := createSendMessagePayload(message, chat, config)
-	_, err := client.SendMessage(&payload)
+	_, err := client.SendMessageContext(ctx, &payload)
 	return err
 }
@@ -1,14 +1,27 @@
 package telegram
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+
 	"github.com/containrrr/shoutrrr/pkg/util/jsonclient"
 )
 
 // Client for Telegram API
 type Client struct {
 	token string
+	// httpClient is used for the API requests, http.DefaultClient is used if it's nil
+	httpClient *http.Client
+}
+
+func (c *Client) jsonClient() jsonclient.Client {
+	if c.httpClient == nil {
+		return jsonclient.DefaultClient
+	}
+	return jsonclient.NewWithHTTPClient(c.httpClient)
 }
 
 func (c *Client) apiURL(endpoint string) string {
@@ -18,10 +31,10 @@ func (c *Client) apiURL(endpoint string) string {
 // GetBotInfo returns the bot User info
 func (c *Client) GetBotInfo() (*User, error) {
 	response := &userResponse{}
-	err := jsonclient.Get(c.apiURL("getMe"), response)
+	err := c.jsonClient().Get(c.apiURL("getMe"), response)
 
 	if !response.OK {
-		return nil, GetErrorResponse(jsonclient.ErrorBody(err))
+		return nil, responseError(err)
 	}
 
 	return &response.Result, nil
@@ -37,10 +50,10 @@ func (c *Client) GetUpdates(offset int, limit int, timeout int, allowedUpdates [
 		AllowedUpdates: allowedUpdates,
 	}
 	response := &updatesResponse{}
-	err := jsonclient.Post(c.apiURL("getUpdates"), request, response)
+	err := c.jsonClient().Post(c.apiURL("getUpdates"), request, response)
 
 	if !response.OK {
-		return nil, GetErrorResponse(jsonclient.ErrorBody(err))
+		return nil, responseError(err)
 	}
 
 	return response.Result, nil
@@ -48,17 +61,34 @@ func (c *Client) GetUpdates(offset int, limit int, timeout int, allowedUpdates [
 
 // SendMessage sends the specified Message
 func (c *Client) SendMessage(message *SendMessagePayload) (*Message, error) {
+	return c.SendMessageContext(context.Background(), message)
+}
+
+// SendMessageContext sends the specified Message, aborting the request when ctx is done
+func (c *Client) SendMessageContext(ctx context.Context, message *SendMessagePayload) (*Message, error) {
 
 	response := &messageResponse{}
-	err := jsonclient.Post(c.apiURL("sendMessage"), message, response)
+	err := c.jsonClient().PostContext(ctx, c.apiURL("sendMessage"), message, response)
 
 	if !response.OK {
-		return nil, GetErrorResponse(jsonclient.ErrorBody(err))
+		return nil, responseError(err)
 	}
 
 	return response.Result, nil
 }
 
+// responseError returns the error response of a failed request, or err itself if the body does not contain one, like
+// for transport errors and cancelled requests
+func responseError(err error) error {
+	if errResponse := GetErrorResponse(jsonclient.ErrorBody(err)); errResponse != nil {
+		return errResponse
+	}
+	if err == nil {
+		return errors.New("the request was not successful")
+	}
+	return err
+}
+
 // GetErrorResponse retrieves the error message from a failed request
 func GetErrorResponse(body string) error {
 	response := &errorResponse{}
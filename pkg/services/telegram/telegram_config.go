@@ -12,7 +12,7 @@ import (
 
 // Config for use within the telegram plugin
 type Config struct {
-	Token        string    `url:"user"`
+	Token        string    `url:"user" secret:""`
 	Preview      bool      `key:"preview" default:"Yes" desc:"If disabled, no web page preview will be displayed for URLs"`
 	Notification bool      `key:"notification" default:"Yes" desc:"If disabled, sends Message silently"`
 	ParseMode    parseMode `key:"parsemode" default:"None" desc:"How the text Message should be parsed"`
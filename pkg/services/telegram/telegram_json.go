@@ -5,6 +5,9 @@ import (
 	"html"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/util"
 )
 
 // SendMessagePayload is the notification payload for the telegram notification service
@@ -73,12 +76,25 @@ type errorResponse struct {
 	OK          bool   `json:"ok"`
 	ErrorCode   int    `json:"error_code"`
 	Description string `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
 }
 
 func (e *errorResponse) Error() string {
 	return e.Description
 }
 
+// Retryable returns whether the error code signals a transient error, like flood control
+func (e *errorResponse) Retryable() bool {
+	return util.IsRetryableStatus(e.ErrorCode)
+}
+
+// RetryAfter returns the delay (in whole seconds) that the API requested before retrying
+func (e *errorResponse) RetryAfter() time.Duration {
+	return time.Duration(e.Parameters.RetryAfter) * time.Second
+}
+
 type userResponse struct {
 	OK     bool `json:"ok"`
 	Result User `json:"result"`
@@ -1,6 +1,7 @@
 package zulip
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/containrrr/shoutrrr/pkg/services/standard"
 	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
 )
 
 // Service sends notifications to a pre-configured channel or user
@@ -23,6 +25,11 @@ const (
 
 // Send a notification message to Zulip
 func (service *Service) Send(message string, params *types.Params) error {
+	return service.SendContext(context.Background(), message, params)
+}
+
+// SendContext sends a notification message to Zulip, aborting the request when ctx is done
+func (service *Service) SendContext(ctx context.Context, message string, params *types.Params) error {
 	// Clone the config because we might modify stream and/or
 	// topic with values from the parameters and they should only
 	// change this Send().
@@ -50,7 +57,7 @@ func (service *Service) Send(message string, params *types.Params) error {
 		return fmt.Errorf("message exceeds max size (%d bytes): was %d bytes", contentMaxSize, messageSize)
 	}
 
-	return service.doSend(config, message)
+	return service.doSend(ctx, config, message)
 }
 
 // Initialize loads ServiceConfig from configURL and sets logger for this Service
@@ -65,17 +72,22 @@ func (service *Service) Initialize(configURL *url.URL, logger types.StdLogger) e
 	return nil
 }
 
-func (service *Service) doSend(config *Config, message string) error {
+func (service *Service) doSend(ctx context.Context, config *Config, message string) error {
 	apiURL := service.getAPIURL(config)
 	payload := CreatePayload(config, message)
-	res, err := http.Post(apiURL, "application/x-www-form-urlencoded", strings.NewReader(payload.Encode()))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(payload.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
+	res, err := service.GetHTTPClient().Do(req)
 	if err == nil && res.StatusCode != http.StatusOK {
-		err = fmt.Errorf("response status code %s", res.Status)
+		err = util.NewStatusError(res, fmt.Errorf("response status code %s", res.Status))
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to send zulip message: %s", err)
+		return fmt.Errorf("failed to send zulip message: %w", err)
 	}
 
 	return nil
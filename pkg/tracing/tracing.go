@@ -0,0 +1,138 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/containrrr/shoutrrr/internal/failures"
+	"github.com/containrrr/shoutrrr/pkg/router"
+	"github.com/containrrr/shoutrrr/pkg/util"
+	"github.com/containrrr/shoutrrr/pkg/util/jsonclient"
+)
+
+// InstrumentationName is the name of the tracer used for creating the spans
+const InstrumentationName = "github.com/containrrr/shoutrrr"
+
+// Attribute keys used for the spans
+const (
+	ServicesKey    = attribute.Key("shoutrrr.services")
+	FailedKey      = attribute.Key("shoutrrr.failed")
+	SchemeKey      = attribute.Key("shoutrrr.scheme")
+	TargetKey      = attribute.Key("shoutrrr.target")
+	PayloadSizeKey = attribute.Key("shoutrrr.payload.size")
+	AttemptsKey    = attribute.Key("shoutrrr.attempts")
+	AttemptKey     = attribute.Key("shoutrrr.attempt")
+	StatusCodeKey  = attribute.Key("http.status_code")
+	FailureIDKey   = attribute.Key("shoutrrr.failure.id")
+)
+
+// Tracer creates OpenTelemetry spans for the notifications sent using the routers it has been added to as an
+// observer. Every notification gets a span, which is a child of any span in the context passed to the router, with a
+// child span for each service used for sending it.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+var _ router.Observer = &Tracer{}
+
+// NewTracer returns a Tracer creating its spans using provider, or using the global provider if provider is nil
+func NewTracer(provider trace.TracerProvider) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &Tracer{tracer: provider.Tracer(InstrumentationName)}
+}
+
+// NotificationStarted implements router.Observer, starting the span of the notification
+func (t *Tracer) NotificationStarted(ctx context.Context, info router.NotificationInfo) context.Context {
+	ctx, _ = t.tracer.Start(ctx, "shoutrrr.send", trace.WithAttributes(
+		ServicesKey.Int(info.Services),
+		PayloadSizeKey.Int(info.Size),
+	))
+	return ctx
+}
+
+// NotificationFinished implements router.Observer, ending the span of the notification
+func (t *Tracer) NotificationFinished(ctx context.Context, _ router.NotificationInfo, results []router.Result) {
+	span := trace.SpanFromContext(ctx)
+
+	failed := 0
+	for _, result := range results {
+		if result.Failed() {
+			failed++
+		}
+	}
+	span.SetAttributes(FailedKey.Int(failed))
+	if failed > 0 {
+		span.SetStatus(codes.Error, fmt.Sprintf("failed to send %d of %d notification(s)", failed, len(results)))
+	}
+
+	span.End()
+}
+
+// ServiceStarted implements router.Observer, starting the span of the service
+func (t *Tracer) ServiceStarted(ctx context.Context, info router.ServiceInfo) context.Context {
+	ctx, _ = t.tracer.Start(ctx, "shoutrrr.send "+info.Scheme, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		SchemeKey.String(info.Scheme),
+		TargetKey.String(target(info.URL)),
+		PayloadSizeKey.Int(info.Size),
+	))
+	return ctx
+}
+
+// AttemptFinished implements router.Observer, adding an event for each failed attempt to the span of the service
+func (t *Tracer) AttemptFinished(ctx context.Context, _ router.ServiceInfo, attempt int, err error) {
+	if err == nil {
+		return
+	}
+	trace.SpanFromContext(ctx).RecordError(err, trace.WithAttributes(append(errorAttributes(err), AttemptKey.Int(attempt))...))
+}
+
+// ServiceFinished implements router.Observer, ending the span of the service
+func (t *Tracer) ServiceFinished(ctx context.Context, _ router.ServiceInfo, result router.Result) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(AttemptsKey.Int(result.Attempts))
+
+	if result.Err != nil {
+		span.SetAttributes(errorAttributes(result.Err)...)
+		span.SetStatus(codes.Error, result.Err.Error())
+	}
+
+	span.End()
+}
+
+// target returns the scheme and host of the service URL, since the user info, path and query often contain tokens
+func target(serviceURL string) string {
+	parsed, err := url.Parse(serviceURL)
+	if err != nil {
+		return ""
+	}
+	return (&url.URL{Scheme: parsed.Scheme, Host: parsed.Host}).String()
+}
+
+// errorAttributes returns the HTTP status code and failure ID of err, if it has any
+func errorAttributes(err error) []attribute.KeyValue {
+	var attributes []attribute.KeyValue
+
+	var statusErr *util.StatusError
+	var jsonErr jsonclient.Error
+	if errors.As(err, &statusErr) {
+		attributes = append(attributes, StatusCodeKey.Int(statusErr.StatusCode))
+	} else if errors.As(err, &jsonErr) {
+		attributes = append(attributes, StatusCodeKey.Int(jsonErr.StatusCode))
+	}
+
+	var failure failures.Failure
+	if errors.As(err, &failure) {
+		attributes = append(attributes, FailureIDKey.Int(int(failure.ID())))
+	}
+
+	return attributes
+}
@@ -0,0 +1,124 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/containrrr/shoutrrr/pkg/router"
+	"github.com/containrrr/shoutrrr/pkg/tracing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestTracing(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Shoutrrr Tracing Suite")
+}
+
+var _ = Describe("the tracer", func() {
+	var recorder *tracetest.SpanRecorder
+	var provider *sdktrace.TracerProvider
+	var client *http.Client
+
+	newRouter := func(urls ...string) *router.ServiceRouter {
+		sr, err := router.New(nil, urls...)
+		Expect(err).NotTo(HaveOccurred())
+		sr.SetHTTPClient(client)
+		sr.AddObserver(tracing.NewTracer(provider))
+		return sr
+	}
+
+	BeforeEach(func() {
+		recorder = tracetest.NewSpanRecorder()
+		provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		client = &http.Client{Transport: &http.Transport{}}
+		httpmock.ActivateNonDefault(client)
+		httpmock.RegisterResponder("POST", "https://ok.example.com/hook", httpmock.NewStringResponder(200, ""))
+		httpmock.RegisterResponder("POST", "https://down.example.com/hook", httpmock.NewStringResponder(503, ""))
+	})
+	AfterEach(func() {
+		httpmock.DeactivateAndReset()
+	})
+
+	It("should create a span for the notification with a child span for each service", func() {
+		sr := newRouter("generic://ok.example.com/hook", "generic://down.example.com/hook")
+		ctx, parent := provider.Tracer("test").Start(context.Background(), "job")
+		sr.SendContext(ctx, "message", nil)
+		parent.End()
+
+		spans := recorder.Ended()
+		Expect(spans).To(HaveLen(4))
+		byName := map[string][]sdktrace.ReadOnlySpan{}
+		for _, span := range spans {
+			byName[span.Name()] = append(byName[span.Name()], span)
+		}
+
+		notification := byName["shoutrrr.send"][0]
+		Expect(notification.Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+		Expect(notification.Attributes()).To(ContainElements(
+			tracing.ServicesKey.Int(2),
+			tracing.PayloadSizeKey.Int(7),
+			tracing.FailedKey.Int(1),
+		))
+		Expect(notification.Status().Code).To(Equal(codes.Error))
+
+		services := byName["shoutrrr.send generic"]
+		Expect(services).To(HaveLen(2))
+		for _, span := range services {
+			Expect(span.Parent().SpanID()).To(Equal(notification.SpanContext().SpanID()))
+			Expect(span.Attributes()).To(ContainElement(tracing.SchemeKey.String("generic")))
+		}
+	})
+	It("should tag the service spans with the target and outcome", func() {
+		sr := newRouter("generic://down.example.com/hook")
+		sr.Send("message", nil)
+
+		var service sdktrace.ReadOnlySpan
+		for _, span := range recorder.Ended() {
+			if span.Name() == "shoutrrr.send generic" {
+				service = span
+			}
+		}
+		Expect(service).NotTo(BeNil())
+		Expect(service.Attributes()).To(ContainElements(
+			tracing.TargetKey.String("generic://down.example.com"),
+			tracing.StatusCodeKey.Int(503),
+			tracing.AttemptsKey.Int(1),
+		))
+		Expect(service.Status().Code).To(Equal(codes.Error))
+		Expect(service.Events()).To(HaveLen(1))
+	})
+	It("should tag the service spans with the status code of JSON API errors", func() {
+		httpmock.RegisterResponder("POST", "https://down.example.com/alerts", httpmock.NewStringResponder(502, "bad gateway"))
+		sr := newRouter("ntfy://down.example.com/alerts")
+		sr.Send("message", nil)
+
+		var attributes []attribute.KeyValue
+		for _, span := range recorder.Ended() {
+			if span.Name() == "shoutrrr.send ntfy" {
+				attributes = span.Attributes()
+			}
+		}
+		Expect(attributes).To(ContainElement(tracing.StatusCodeKey.Int(502)))
+	})
+	It("should tag the service spans with the failure ID", func() {
+		sr := newRouter("smtp://127.0.0.1:1/?fromAddress=from@host.tld&toAddresses=to@host.tld")
+		sr.Send("message", nil)
+
+		var attributes []attribute.KeyValue
+		for _, span := range recorder.Ended() {
+			if span.Name() == "shoutrrr.send smtp" {
+				attributes = span.Attributes()
+			}
+		}
+		Expect(attributes).To(ContainElement(HaveField("Key", tracing.FailureIDKey)))
+	})
+})
@@ -0,0 +1,18 @@
+package types
+
+import (
+	"context"
+	"net"
+)
+
+// ContextDialer makes network connections, like net.Dialer
+type ContextDialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// DialerService is the interface for services that make their own network connections, instead of using an
+// http.Client
+type DialerService interface {
+	// SetDialer sets the dialer used for the connections, replacing the default one
+	SetDialer(dialer ContextDialer)
+}
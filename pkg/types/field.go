@@ -21,9 +21,8 @@ func FieldsFromMap(fieldMap map[string]string, sorted bool) []Field {
 		sort.Strings(keys)
 	}
 
-	for i, key := range keys {
-		fields[i].Key = key
-		fields[i].Value = fieldMap[key]
+	for _, key := range keys {
+		fields = append(fields, Field{Key: key, Value: fieldMap[key]})
 	}
 
 	return fields
@@ -0,0 +1,40 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestTypes(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Shoutrrr Types Suite")
+}
+
+var _ = Describe("the fields", func() {
+	When("creating fields from a map", func() {
+		It("should return a field for every key", func() {
+			fields := types.FieldsFromMap(map[string]string{"title": "Test", "priority": "high"}, false)
+
+			Expect(fields).To(ConsistOf(
+				types.Field{Key: "title", Value: "Test"},
+				types.Field{Key: "priority", Value: "high"},
+			))
+		})
+		It("should sort the fields by key when requested", func() {
+			fields := types.FieldsFromMap(map[string]string{"c": "3", "a": "1", "b": "2"}, true)
+
+			Expect(fields).To(Equal([]types.Field{
+				{Key: "a", Value: "1"},
+				{Key: "b", Value: "2"},
+				{Key: "c", Value: "3"},
+			}))
+		})
+		It("should return no fields for an empty map", func() {
+			Expect(types.FieldsFromMap(map[string]string{}, true)).To(BeEmpty())
+		})
+	})
+})
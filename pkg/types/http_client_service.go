@@ -0,0 +1,12 @@
+package types
+
+import "net/http"
+
+// HTTPClientService is the interface for services that make their requests using a replaceable http.Client
+type HTTPClientService interface {
+	// GetHTTPClient returns the client used for HTTP requests
+	GetHTTPClient() *http.Client
+
+	// SetHTTPClient replaces the client used for HTTP requests
+	SetHTTPClient(client *http.Client)
+}
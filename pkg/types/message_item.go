@@ -1,6 +1,7 @@
 package types
 
 import (
+	"fmt"
 	"strings"
 	"time"
 )
@@ -39,6 +40,16 @@ func (level MessageLevel) String() string {
 	return messageLevelStrings[level]
 }
 
+// ParseMessageLevel returns the MessageLevel with the given name, ignoring case
+func ParseMessageLevel(name string) (MessageLevel, error) {
+	for level, levelName := range messageLevelStrings {
+		if strings.EqualFold(name, levelName) {
+			return MessageLevel(level), nil
+		}
+	}
+	return Unknown, fmt.Errorf("invalid message level %q", name)
+}
+
 // MessageItem is an entry in a notification being sent by a service
 type MessageItem struct {
 	Text      string
@@ -8,3 +8,8 @@ type MessageLimit struct {
 	// Maximum number of chunks (including the last chunk for meta data)
 	ChunkCount int
 }
+
+// MessageLimiter is implemented by services that declare the payload limits of their upstream APIs
+type MessageLimiter interface {
+	MessageLimit() MessageLimit
+}
@@ -0,0 +1,12 @@
+package types
+
+import "time"
+
+// RetryableError is the interface implemented by errors that know whether the failed operation is worth retrying
+type RetryableError interface {
+	error
+	// Retryable returns whether the error was caused by a transient condition, like rate limiting
+	Retryable() bool
+	// RetryAfter returns the delay requested by the server before retrying, or zero if none was requested
+	RetryAfter() time.Duration
+}
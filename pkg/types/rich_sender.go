@@ -1,6 +1,15 @@
 package types
 
+import "context"
+
 // RichSender is the interface needed to implement to send rich notifications
 type RichSender interface {
-	SendItems(items []MessageItem, params Params) error
+	SendItems(items []MessageItem, params *Params) error
+}
+
+// ContextRichSender is the interface implemented by rich senders that can abort sending the items when a context is
+// done. Rich senders that do not implement it are called using SendItems, and are not aborted.
+type ContextRichSender interface {
+	// SendItemsContext sends the items, aborting any pending requests when the context is done
+	SendItemsContext(ctx context.Context, items []MessageItem, params *Params) error
 }
@@ -1,9 +1,17 @@
 package types
 
+import "context"
+
 // Sender is the interface needed to implement to send notifications
 type Sender interface {
 	Send(message string, params *Params) error
 
-	// Rich sender API:
-	// SendItems(items []MessageItem, params *Params) error
+	// Services that support rich notifications also implement the RichSender interface
+}
+
+// ContextSender is the interface implemented by senders that can abort sending a notification when a context is done.
+// Senders that do not implement it are called using Send, and are not aborted.
+type ContextSender interface {
+	// SendContext sends the notification, aborting any pending requests when the context is done
+	SendContext(ctx context.Context, message string, params *Params) error
 }
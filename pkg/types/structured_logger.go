@@ -0,0 +1,43 @@
+package types
+
+import (
+	"context"
+	"fmt"
+)
+
+// LogLevel is the severity of a structured log record. The values match the ones of the log/slog levels.
+type LogLevel int
+
+// Log levels, ordered by severity
+const (
+	LogLevelDebug LogLevel = -4
+	LogLevelInfo  LogLevel = 0
+	LogLevelWarn  LogLevel = 4
+	LogLevelError LogLevel = 8
+)
+
+func (level LogLevel) String() string {
+	switch level {
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	}
+	return fmt.Sprintf("LEVEL(%d)", int(level))
+}
+
+// StructuredLogger is a leveled logger, that attaches key/value attributes to the records it writes
+type StructuredLogger interface {
+	// Log writes a record with the level and message, the attributes are given as alternating keys and values
+	Log(ctx context.Context, level LogLevel, msg string, keyvals ...interface{})
+}
+
+// StructuredLoggerService is the interface for services that can write structured log records
+type StructuredLoggerService interface {
+	// SetStructuredLogger sets the logger used for the service logs, replacing the StdLogger passed to Initialize
+	SetStructuredLogger(logger StructuredLogger)
+}
@@ -0,0 +1,9 @@
+package types
+
+import "crypto/tls"
+
+// TLSConfigService is the interface for services that make their own TLS connections, instead of using an http.Client
+type TLSConfigService interface {
+	// SetTLSConfig sets the config used for the TLS connections, replacing the default one
+	SetTLSConfig(config *tls.Config)
+}
@@ -1,12 +1,18 @@
 package jsonclient
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/util"
+)
 
 // Error contains additional http/JSON details
 type Error struct {
 	StatusCode int
 	Body       string
 	err        error
+	retryAfter time.Duration
 }
 
 func (je Error) Error() string {
@@ -20,6 +26,16 @@ func (je Error) String() string {
 	return je.err.Error()
 }
 
+// Retryable returns whether the response status signals a transient error, like rate limiting
+func (je Error) Retryable() bool {
+	return util.IsRetryableStatus(je.StatusCode)
+}
+
+// RetryAfter returns the delay requested by the server using the Retry-After header, or zero if none was requested
+func (je Error) RetryAfter() time.Duration {
+	return je.retryAfter
+}
+
 // ErrorBody returns the request body from an Error
 func ErrorBody(e error) string {
 	if jsonError, ok := e.(Error); ok {
@@ -2,10 +2,14 @@ package jsonclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/util"
 )
 
 // ContentType is the default mime type for JSON
@@ -24,6 +28,18 @@ func Post(url string, request interface{}, response interface{}) error {
 	return DefaultClient.Post(url, request, response)
 }
 
+// GetContext fetches url using GET and unmarshals into the passed response using DefaultClient,
+// aborting the request when ctx is done
+func GetContext(ctx context.Context, url string, response interface{}) error {
+	return DefaultClient.GetContext(ctx, url, response)
+}
+
+// PostContext sends request as JSON and unmarshals the response JSON into the supplied struct using DefaultClient,
+// aborting the request when ctx is done
+func PostContext(ctx context.Context, url string, request interface{}, response interface{}) error {
+	return DefaultClient.PostContext(ctx, url, request, response)
+}
+
 // Client is a JSON wrapper around http.Client
 type client struct {
 	httpClient *http.Client
@@ -53,7 +69,17 @@ func (c *client) Headers() http.Header {
 
 // Get fetches url using GET and unmarshals into the passed response
 func (c *client) Get(url string, response interface{}) error {
-	res, err := c.httpClient.Get(url)
+	return c.GetContext(context.Background(), url, response)
+}
+
+// GetContext fetches url using GET and unmarshals into the passed response, aborting the request when ctx is done
+func (c *client) GetContext(ctx context.Context, url string, response interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	res, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -63,6 +89,12 @@ func (c *client) Get(url string, response interface{}) error {
 
 // Post sends request as JSON and unmarshals the response JSON into the supplied struct
 func (c *client) Post(url string, request interface{}, response interface{}) error {
+	return c.PostContext(context.Background(), url, request, response)
+}
+
+// PostContext sends request as JSON and unmarshals the response JSON into the supplied struct,
+// aborting the request when ctx is done
+func (c *client) PostContext(ctx context.Context, url string, request interface{}, response interface{}) error {
 	var err error
 	var body []byte
 
@@ -76,7 +108,7 @@ func (c *client) Post(url string, request interface{}, response interface{}) err
 		}
 	}
 
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
 	}
@@ -123,6 +155,7 @@ func parseResponse(res *http.Response, response interface{}) error {
 			StatusCode: res.StatusCode,
 			Body:       string(body),
 			err:        err,
+			retryAfter: util.ParseRetryAfter(res.Header.Get("Retry-After"), time.Now()),
 		}
 	}
 
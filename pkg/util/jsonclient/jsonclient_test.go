@@ -1,9 +1,11 @@
 package jsonclient_test
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/containrrr/shoutrrr/pkg/util/jsonclient"
 	"github.com/onsi/gomega/ghttp"
@@ -80,6 +82,17 @@ var _ = Describe("JSONClient", func() {
 			Expect(err).To(MatchError("got HTTP 404 Not Found"))
 		})
 
+		It("should return a retryable error when rate limited", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusTooManyRequests, "", http.Header{"Retry-After": {"3"}}))
+			err := jsonclient.Post(server.URL(), &mockRequest{}, &mockResponse{})
+			Expect(err).To(HaveOccurred())
+
+			jsonErr := &jsonclient.Error{}
+			Expect(errors.As(err, jsonErr)).To(BeTrue())
+			Expect(jsonErr.Retryable()).To(BeTrue())
+			Expect(jsonErr.RetryAfter()).To(Equal(3 * time.Second))
+		})
+
 		It("should return error on invalid request", func() {
 			server.AppendHandlers(ghttp.VerifyRequest("POST", "/"))
 			err := jsonclient.Post(server.URL(), func() {}, &mockResponse{})
@@ -87,6 +100,15 @@ var _ = Describe("JSONClient", func() {
 			Expect(err).To(MatchError("error creating payload: json: unsupported type: func()"))
 		})
 
+		It("should abort the request when the context is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			err := jsonclient.PostContext(ctx, server.URL(), &mockRequest{}, &mockResponse{})
+			Expect(server.ReceivedRequests()).Should(HaveLen(0))
+			Expect(err).To(MatchError(context.Canceled))
+		})
+
 		It("should return error on invalid response type", func() {
 			res := &mockResponse{Status: "cool skirt"}
 			server.AppendHandlers(ghttp.CombineHandlers(
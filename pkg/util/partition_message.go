@@ -16,7 +16,8 @@ func PartitionMessage(input string, limits t.MessageLimit, distance int) (items
 	runes := []rune(input)
 	chunkOffset := 0
 	maxTotal := Min(len(runes), limits.TotalChunkSize)
-	maxCount := limits.ChunkCount - 1
+	// The last chunk is reserved, unless that would leave no chunks at all
+	maxCount := Max(limits.ChunkCount-1, 1)
 
 	if len(input) == 0 {
 		// If the message is empty, return an empty array
@@ -80,27 +81,30 @@ func MessageItemsFromLines(plain string, limits t.MessageLimit) (batches [][]t.M
 
 		maxLen := limits.ChunkSize
 
-		if len(items) == maxCount || totalLength+maxLen > limits.TotalChunkSize {
-			batches = append(batches, items)
-			items = items[:0]
-		}
-
 		runes := []rune(line)
 		if len(runes) > maxLen {
 			// Trim and add ellipsis
-			runes = runes[:maxLen-len(ellipsis)]
-			line = string(runes) + ellipsis
+			line = string(runes[:maxLen-len(ellipsis)]) + ellipsis
+			runes = []rune(line)
 		}
 
 		if len(runes) < 1 {
 			continue
 		}
 
+		// Only start a new batch if the current one has any items, as an empty batch would be sent as an empty message
+		if len(items) > 0 && (len(items) == maxCount || totalLength+len(runes) > limits.TotalChunkSize) {
+			batches = append(batches, items)
+			items = make([]t.MessageItem, 0, Min(maxCount, len(lines)))
+			totalLength = 0
+		}
+
 		items = append(items, t.MessageItem{
 			Text: line,
 		})
 
-		totalLength += len(runes)
+		// Count the line separator as well, so that the batch also fits the limits when joined as plain text
+		totalLength += len(runes) + 1
 	}
 
 	if len(items) > 0 {
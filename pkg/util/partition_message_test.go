@@ -26,6 +26,16 @@ var _ = Describe("Partition Message", func() {
 				Expect(len(items[1].Text)).To(Equal(1999))
 				Expect(len(items[2].Text)).To(Equal(205))
 			})
+			It("should return a single chunk if the count is limited to one", func() {
+				items, _ := testPartitionMessage(42, types.MessageLimit{
+					ChunkSize:      2000,
+					TotalChunkSize: 2000,
+					ChunkCount:     1,
+				}, 100)
+
+				Expect(items).To(HaveLen(1))
+				Expect(len(items[0].Text)).To(Equal(2000))
+			})
 			It("omit characters above total max", func() {
 				items, _ := testPartitionMessage(62, limits, 100)
 
@@ -127,6 +137,52 @@ var _ = Describe("Partition Message", func() {
 					Expect(len(batches)).To(Equal(2))
 				})
 			})
+			It("should not change earlier batches when creating new ones", func() {
+				batches := MessageItemsFromLines("one\ntwo\nthree\nfour\nfive", types.MessageLimit{
+					ChunkSize:      10,
+					TotalChunkSize: 100,
+					ChunkCount:     2,
+				})
+
+				Expect(batches).To(HaveLen(3))
+				Expect(batches[0][0].Text).To(Equal("one"))
+				Expect(batches[1][0].Text).To(Equal("three"))
+				Expect(batches[2][0].Text).To(Equal("five"))
+			})
+			It("should fit the batches within the total size when joined by newlines", func() {
+				batches := MessageItemsFromLines("1234\n1234\n1234", types.MessageLimit{
+					ChunkSize:      10,
+					TotalChunkSize: 10,
+					ChunkCount:     10,
+				})
+
+				Expect(batches).To(HaveLen(2))
+				Expect(batches[0]).To(HaveLen(2))
+			})
+			It("should not create an empty batch when the first line is above the total size", func() {
+				batches := MessageItemsFromLines("1234567890\n1234", types.MessageLimit{
+					ChunkSize:      20,
+					TotalChunkSize: 8,
+					ChunkCount:     10,
+				})
+
+				Expect(batches).To(HaveLen(2))
+				Expect(batches[0]).To(HaveLen(1))
+				Expect(batches[1]).To(HaveLen(1))
+			})
+			It("should count the ellipsis of trimmed lines towards the total size", func() {
+				batches := MessageItemsFromLines("12345678901234\n12345678901234", types.MessageLimit{
+					ChunkSize:      10,
+					TotalChunkSize: 20,
+					ChunkCount:     10,
+				})
+
+				Expect(batches).To(HaveLen(2))
+				for _, items := range batches {
+					Expect(items).To(HaveLen(1))
+					Expect(items[0].Text).To(Equal("1234 [...]"))
+				}
+			})
 			It("should trim characters above chunk size", func() {
 				hundreds := 42
 				repeat := 21
@@ -0,0 +1,64 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// LoggerWith returns a StructuredLogger that adds the attributes, given as alternating keys and values, to every
+// record written to logger
+func LoggerWith(logger types.StructuredLogger, keyvals ...interface{}) types.StructuredLogger {
+	if with, ok := logger.(*attrLogger); ok {
+		return &attrLogger{logger: with.logger, keyvals: append(append([]interface{}{}, with.keyvals...), keyvals...)}
+	}
+	return &attrLogger{logger: logger, keyvals: keyvals}
+}
+
+type attrLogger struct {
+	logger  types.StructuredLogger
+	keyvals []interface{}
+}
+
+func (al *attrLogger) Log(ctx context.Context, level types.LogLevel, msg string, keyvals ...interface{}) {
+	al.logger.Log(ctx, level, msg, append(append([]interface{}{}, al.keyvals...), keyvals...)...)
+}
+
+// NewStdStructuredLogger returns a StructuredLogger that writes its records to logger, formatted as the level,
+// message and attributes, e.g. `WARN retrying send attempt=2`
+func NewStdStructuredLogger(logger types.StdLogger) types.StructuredLogger {
+	if logger == nil {
+		logger = DiscardLogger
+	}
+	return &stdStructuredLogger{logger}
+}
+
+type stdStructuredLogger struct {
+	logger types.StdLogger
+}
+
+func (sl *stdStructuredLogger) Log(_ context.Context, level types.LogLevel, msg string, keyvals ...interface{}) {
+	sl.logger.Print(level.String() + " " + FormatLogRecord(msg, keyvals...))
+}
+
+// FormatLogRecord formats the message and attributes of a log record as `msg key=value key2=value2`.
+// Values containing spaces or quotes are quoted.
+func FormatLogRecord(msg string, keyvals ...interface{}) string {
+	sb := strings.Builder{}
+	sb.WriteString(msg)
+	for i := 0; i < len(keyvals); i += 2 {
+		var value interface{} = "(MISSING)"
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+
+		formatted := fmt.Sprint(value)
+		if formatted == "" || strings.ContainsAny(formatted, " \t\n\"=") {
+			formatted = fmt.Sprintf("%q", formatted)
+		}
+		fmt.Fprintf(&sb, " %v=%s", keyvals[i], formatted)
+	}
+	return sb.String()
+}
@@ -0,0 +1,29 @@
+//go:build go1.21
+
+package util
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// NewSlogLogger returns a StructuredLogger that writes its records to logger, or to slog.Default() if logger is nil
+func NewSlogLogger(logger *slog.Logger) types.StructuredLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &slogLogger{logger}
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (sl *slogLogger) Log(ctx context.Context, level types.LogLevel, msg string, keyvals ...interface{}) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	sl.logger.Log(ctx, slog.Level(level), msg, keyvals...)
+}
@@ -0,0 +1,35 @@
+//go:build go1.21
+
+package util_test
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+	. "github.com/containrrr/shoutrrr/pkg/util"
+)
+
+var _ = Describe("the slog logger adapter", func() {
+	It("should write the records with the corresponding level and attributes", func() {
+		builder := &strings.Builder{}
+		handler := slog.NewTextHandler(builder, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+			ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+				if attr.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return attr
+			},
+		})
+		logger := NewSlogLogger(slog.New(handler))
+
+		logger.Log(context.Background(), types.LogLevelDebug, "sending", "service", "teams")
+		logger.Log(context.Background(), types.LogLevelWarn, "retrying", "attempt", 2)
+		Expect(builder.String()).To(Equal("level=DEBUG msg=sending service=teams\nlevel=WARN msg=retrying attempt=2\n"))
+	})
+})
@@ -0,0 +1,52 @@
+package util_test
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+	. "github.com/containrrr/shoutrrr/pkg/util"
+)
+
+// recordingLogger records the records written to it, formatted using FormatLogRecord
+type recordingLogger struct {
+	records []string
+}
+
+func (rl *recordingLogger) Log(_ context.Context, level types.LogLevel, msg string, keyvals ...interface{}) {
+	rl.records = append(rl.records, level.String()+" "+FormatLogRecord(msg, keyvals...))
+}
+
+var _ = Describe("the structured logger utils", func() {
+	When("formatting a log record", func() {
+		It("should append the attributes to the message", func() {
+			Expect(FormatLogRecord("sent", "service", "discord", "attempt", 2)).To(Equal("sent service=discord attempt=2"))
+		})
+		It("should quote values containing spaces", func() {
+			Expect(FormatLogRecord("failed", "error", "connection refused")).To(Equal(`failed error="connection refused"`))
+		})
+		It("should mark a missing value", func() {
+			Expect(FormatLogRecord("failed", "error")).To(Equal("failed error=(MISSING)"))
+		})
+	})
+	When("adding attributes to a logger", func() {
+		It("should prepend them to the attributes of every record", func() {
+			recorder := &recordingLogger{}
+			logger := LoggerWith(LoggerWith(recorder, "service", "matrix"), "operation", "login")
+			logger.Log(context.Background(), types.LogLevelWarn, "retrying", "attempt", 2)
+			Expect(recorder.records).To(Equal([]string{"WARN retrying service=matrix operation=login attempt=2"}))
+		})
+	})
+	When("using a std logger as a structured logger", func() {
+		It("should write the formatted records to it", func() {
+			builder := &strings.Builder{}
+			logger := NewStdStructuredLogger(log.New(builder, "", 0))
+			logger.Log(context.Background(), types.LogLevelError, "failed", "service", "smtp")
+			Expect(builder.String()).To(Equal("ERROR failed service=smtp\n"))
+		})
+	})
+})
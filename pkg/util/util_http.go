@@ -0,0 +1,80 @@
+package util
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StatusError wraps an error caused by an unsuccessful HTTP response, keeping the status code and
+// any delay requested by the server using the Retry-After header
+type StatusError struct {
+	StatusCode int
+	err        error
+	retryAfter time.Duration
+}
+
+// NewStatusError creates a StatusError for the response, with err as the wrapped error
+func NewStatusError(res *http.Response, err error) *StatusError {
+	return &StatusError{
+		StatusCode: res.StatusCode,
+		err:        err,
+		retryAfter: ParseRetryAfter(res.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+func (se *StatusError) Error() string {
+	return se.err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (se *StatusError) Unwrap() error {
+	return se.err
+}
+
+// Retryable returns whether the status code signals a transient error
+func (se *StatusError) Retryable() bool {
+	return IsRetryableStatus(se.StatusCode)
+}
+
+// RetryAfter returns the delay requested by the server, or zero if none was requested
+func (se *StatusError) RetryAfter() time.Duration {
+	return se.retryAfter
+}
+
+// IsRetryableStatus returns whether the HTTP status code signals a transient error, like rate limiting
+// or a temporarily unavailable server
+func IsRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// ParseRetryAfter parses the value of a Retry-After header, either given in (possibly fractional) seconds or as
+// a HTTP date, returning the delay relative to now. Zero is returned for missing or invalid values.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds * float64(time.Second))
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := date.Sub(now); delay > 0 {
+			return delay
+		}
+	}
+
+	return 0
+}
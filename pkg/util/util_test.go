@@ -1,9 +1,12 @@
 package util_test
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"reflect"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -86,6 +89,37 @@ var _ = Describe("the util package", func() {
 		})
 	})
 
+	When("parsing a Retry-After header value", func() {
+		now := time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC)
+		It("should return the delay given in seconds", func() {
+			Expect(ParseRetryAfter("120", now)).To(Equal(2 * time.Minute))
+			Expect(ParseRetryAfter("0.5", now)).To(Equal(500 * time.Millisecond))
+		})
+		It("should return the delay until the given date", func() {
+			Expect(ParseRetryAfter("Sun, 02 Jan 2022 15:05:05 GMT", now)).To(Equal(time.Minute))
+		})
+		It("should return zero for missing, invalid or past values", func() {
+			Expect(ParseRetryAfter("", now)).To(BeZero())
+			Expect(ParseRetryAfter("soon", now)).To(BeZero())
+			Expect(ParseRetryAfter("-5", now)).To(BeZero())
+			Expect(ParseRetryAfter("Sun, 02 Jan 2022 15:00:00 GMT", now)).To(BeZero())
+		})
+	})
+
+	When("creating a status error from a response", func() {
+		It("should be retryable for transient status codes", func() {
+			res := &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": {"10"}}}
+			err := NewStatusError(res, errors.New("service unavailable"))
+			Expect(err).To(MatchError("service unavailable"))
+			Expect(err.Retryable()).To(BeTrue())
+			Expect(err.RetryAfter()).To(Equal(10 * time.Second))
+		})
+		It("should not be retryable for client errors", func() {
+			res := &http.Response{StatusCode: http.StatusBadRequest, Header: http.Header{}}
+			Expect(NewStatusError(res, errors.New("bad request")).Retryable()).To(BeFalse())
+		})
+	})
+
 	When("calling function DocsURL", func() {
 		It("should return the expected URL", func() {
 			expectedBase := fmt.Sprintf(`https://containrrr.dev/shoutrrr/%s/`, meta.DocsVersion)
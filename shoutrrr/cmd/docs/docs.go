@@ -12,10 +12,7 @@ import (
 	cli "github.com/containrrr/shoutrrr/shoutrrr/cmd"
 )
 
-var (
-	serviceRouter router.ServiceRouter
-	services      = serviceRouter.ListServices()
-)
+var serviceRouter router.ServiceRouter
 
 // Cmd prints documentation for services
 var Cmd = &cobra.Command{
@@ -23,11 +20,14 @@ var Cmd = &cobra.Command{
 	Short: "Print documentation for services",
 	Run:   Run,
 	Args: func(cmd *cobra.Command, args []string) error {
-		serviceList := strings.Join(services, ", ")
+		// Listed when the command is run, to include any services registered after this package was initialized
+		serviceList := strings.Join(serviceRouter.ListServices(), ", ")
 		cmd.SetUsageTemplate(cmd.UsageTemplate() + "\nAvailable services: \n  " + serviceList + "\n")
 		return cobra.MinimumNArgs(1)(cmd, args)
 	},
-	ValidArgs: services,
+	ValidArgsFunction: func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return serviceRouter.ListServices(), cobra.ShellCompDirectiveNoFileComp
+	},
 }
 
 func init() {
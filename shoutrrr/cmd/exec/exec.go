@@ -0,0 +1,240 @@
+package exec
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	osexec "os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/containrrr/shoutrrr/internal/dedupe"
+	"github.com/containrrr/shoutrrr/pkg/router"
+	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
+	cli "github.com/containrrr/shoutrrr/shoutrrr/cmd"
+)
+
+// Notify modes, deciding which outcomes are notified about
+const (
+	NotifyFailure = "failure"
+	NotifyAlways  = "always"
+	NotifyChange  = "change"
+)
+
+// exitNotStarted is the exit code used if the command could not be started, like shells do for unknown commands
+const exitNotStarted = 127
+
+// Cmd runs a command and sends a notification about its outcome
+var Cmd = &cobra.Command{
+	Use:   "exec [flags] -- command [args...]",
+	Short: "Run a command and send a notification about its outcome using the service urls",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  Run,
+}
+
+func init() {
+	// Stop parsing flags at the command, so that its flags are passed on to it
+	Cmd.Flags().SetInterspersed(false)
+
+	Cmd.Flags().BoolP("verbose", "v", false, "")
+
+	Cmd.Flags().StringArrayP("url", "u", []string{}, "The notification url")
+	_ = Cmd.MarkFlagRequired("url")
+
+	Cmd.Flags().StringP("notify", "n", NotifyFailure,
+		"When to send a notification: failure, always, or change (when the outcome differs from the last run)")
+	Cmd.Flags().String("title", DefaultTitleTemplate, "The template used for the notification title")
+	Cmd.Flags().String("template", DefaultMessageTemplate, "The template used for the notification message")
+	Cmd.Flags().Int("tail", 20, "The number of output lines included in the notification")
+	Cmd.Flags().String("state-file", "", "The file storing the outcome of the last run, for --notify change "+
+		"(defaults to a file in the user cache dir, named after the command)")
+}
+
+// target is a router with one of the service URLs, along with the message limits of the service
+type target struct {
+	router *router.ServiceRouter
+	limit  types.MessageLimit
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+	verbose, _ := flags.GetBool("verbose")
+	urls, _ := flags.GetStringArray("url")
+	urls = dedupe.RemoveDuplicates(urls)
+	notify, _ := flags.GetString("notify")
+	titleTemplate, _ := flags.GetString("title")
+	messageTemplate, _ := flags.GetString("template")
+	tail, _ := flags.GetInt("tail")
+	stateFile, _ := flags.GetString("state-file")
+
+	if notify != NotifyFailure && notify != NotifyAlways && notify != NotifyChange {
+		return cli.InvalidUsage(fmt.Sprintf("invalid notify mode %q, expected failure, always or change", notify))
+	}
+
+	tpls, err := parseTemplates(titleTemplate, messageTemplate)
+	if err != nil {
+		return cli.InvalidUsage(err.Error())
+	}
+
+	var logger types.StdLogger = util.DiscardLogger
+	if verbose {
+		logger = log.New(os.Stderr, "SHOUTRRR ", log.LstdFlags)
+	}
+
+	// Create the routers before running the command, so that invalid URLs are reported without running it
+	targets, err := createTargets(urls, logger)
+	if err != nil {
+		return cli.ConfigurationError(fmt.Sprintf("error invoking exec: %s", err))
+	}
+
+	outcome := runCommand(args, tail)
+
+	shouldNotify := notify == NotifyAlways || !outcome.Success
+	if notify == NotifyChange {
+		if stateFile == "" {
+			stateFile = defaultStateFile(args)
+		}
+		if shouldNotify, err = stateChanged(stateFile, outcome); err != nil {
+			logf("Failed to update the state file: %v", err)
+			shouldNotify = !outcome.Success
+		}
+	}
+
+	notified := true
+	if shouldNotify {
+		notified = sendNotification(targets, tpls, outcome)
+	}
+
+	if outcome.ExitCode != 0 {
+		return cli.Result{ExitCode: outcome.ExitCode, Message: fmt.Sprintf("%s exited with code %d", outcome.Command, outcome.ExitCode)}
+	}
+	if !notified {
+		return cli.TaskUnavailable("failed to send the notification")
+	}
+
+	return nil
+}
+
+func createTargets(urls []string, logger types.StdLogger) ([]target, error) {
+	targets := make([]target, 0, len(urls))
+	for _, serviceURL := range urls {
+		sr, err := router.New(logger, serviceURL)
+		if err != nil {
+			return nil, err
+		}
+
+		limit, ok := sr.MessageLimit(0)
+		if !ok {
+			limit = defaultMessageLimit
+		}
+
+		targets = append(targets, target{router: sr, limit: limit})
+	}
+	return targets, nil
+}
+
+// runCommand runs the command, passing its output on while keeping the tail of it for the notification
+func runCommand(args []string, tail int) Outcome {
+	outcome := Outcome{Command: strings.Join(args, " ")}
+	outcome.Host, _ = os.Hostname()
+
+	output := newLineTail(tail)
+	command := osexec.Command(args[0], args[1:]...)
+	command.Stdin = os.Stdin
+	command.Stdout = io.MultiWriter(os.Stdout, output)
+	command.Stderr = io.MultiWriter(os.Stderr, output)
+
+	start := time.Now()
+	if err := command.Start(); err != nil {
+		outcome.ExitCode = exitNotStarted
+		outcome.Output = fmt.Sprintf("failed to start command: %v", err)
+		return outcome
+	}
+
+	// Pass the signals for stopping on to the command, and let it decide when to exit
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		for sig := range signals {
+			_ = command.Process.Signal(sig)
+		}
+	}()
+
+	_ = command.Wait()
+	outcome.Duration = time.Since(start).Round(time.Millisecond)
+	outcome.ExitCode = command.ProcessState.ExitCode()
+	if status, ok := command.ProcessState.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		// Report commands killed by a signal the way shells do
+		outcome.ExitCode = 128 + int(status.Signal())
+	}
+	outcome.Success = outcome.ExitCode == 0
+	outcome.Output = output.String()
+
+	return outcome
+}
+
+// sendNotification sends the notification about the outcome using every target, returning whether all succeeded
+func sendNotification(targets []target, tpls *templates, outcome Outcome) bool {
+	title, err := tpls.title(outcome)
+	if err != nil {
+		logf("Failed to render the title: %v", err)
+		return false
+	}
+
+	success := true
+	for _, t := range targets {
+		items, err := tpls.items(outcome, t.limit)
+		if err != nil {
+			logf("Failed to render the message: %v", err)
+			return false
+		}
+
+		params := types.Params{}
+		params.SetTitle(title)
+		for _, result := range t.router.SendItemsResults(context.Background(), items, params) {
+			if result.Failed() {
+				success = false
+				logf("%v", result)
+			}
+		}
+	}
+	return success
+}
+
+// defaultStateFile returns the path of the state file for the command, in the user cache dir
+func defaultStateFile(args []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(args, "\x00")))
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "shoutrrr", "exec", hex.EncodeToString(sum[:8])+".state")
+}
+
+func logf(format string, a ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", a...)
+}
+
+// Run the exec command
+func Run(cmd *cobra.Command, args []string) error {
+	err := run(cmd, args)
+	if err != nil {
+		if result, ok := err.(cli.Result); ok && result.ExitCode != cli.ExUsage {
+			// If the error is not related to the CLI usage, report error and exit to not invoke cobra error output
+			_, _ = fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(result.ExitCode)
+		}
+	}
+	return err
+}
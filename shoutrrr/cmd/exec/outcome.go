@@ -0,0 +1,206 @@
+package exec
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
+)
+
+// DefaultTitleTemplate is the template used for the notification title if none is set
+const DefaultTitleTemplate = `{{if .Success}}Succeeded{{else}}Failed{{end}}: {{.Command}}`
+
+// DefaultMessageTemplate is the template used for the notification message if none is set
+const DefaultMessageTemplate = `{{.Command}} {{if .Success}}succeeded{{else}}failed with exit code {{.ExitCode}}{{end}} after {{.Duration}} on {{.Host}}
+{{- if .Output}}
+
+{{.Output}}{{end}}`
+
+// omittedOutput is prepended to the output if the start of it was cut to fit the message limits of a service
+const omittedOutput = "[...] "
+
+// splitDistance is how far back from the chunk size a whitespace is searched for, when splitting the message
+const splitDistance = 100
+
+// defaultMessageLimit is used for the services that do not declare the message limits of their API. It is the
+// smallest limit of the common services, so that the output is cut instead of being rejected or truncated by them.
+var defaultMessageLimit = types.MessageLimit{ChunkSize: 1024, TotalChunkSize: 1024, ChunkCount: 1}
+
+// Outcome is the result of running the command, available to the templates
+type Outcome struct {
+	// Command is the command line that was run
+	Command string
+	// ExitCode is the exit code of the command, 128 plus the signal number if it was killed by a signal,
+	// or 127 if it could not be started
+	ExitCode int
+	// Duration is the time the command ran for, rounded to milliseconds
+	Duration time.Duration
+	// Output is the tail of the combined stdout and stderr of the command
+	Output string
+	// Success is whether the command exited with exit code 0
+	Success bool
+	// Host is the hostname of the machine the command ran on
+	Host string
+}
+
+// Level returns the message level corresponding to the outcome
+func (o Outcome) Level() types.MessageLevel {
+	if o.Success {
+		return types.Info
+	}
+	return types.Error
+}
+
+// templates renders the notification title and message for outcomes
+type templates struct {
+	titleTemplate   *template.Template
+	messageTemplate *template.Template
+}
+
+func parseTemplates(title string, message string) (*templates, error) {
+	titleTemplate, err := template.New("title").Parse(title)
+	if err != nil {
+		return nil, fmt.Errorf("invalid title template: %w", err)
+	}
+	messageTemplate, err := template.New("message").Parse(message)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message template: %w", err)
+	}
+	return &templates{titleTemplate: titleTemplate, messageTemplate: messageTemplate}, nil
+}
+
+func (t *templates) title(outcome Outcome) (string, error) {
+	return render(t.titleTemplate, outcome)
+}
+
+// items renders the message for the outcome as message items fitting the limits. If the message does not fit, the
+// start of the output is cut, since the last lines are the most likely to explain a failure.
+func (t *templates) items(outcome Outcome, limit types.MessageLimit) ([]types.MessageItem, error) {
+	message, err := render(t.messageTemplate, outcome)
+	if err != nil {
+		return nil, err
+	}
+
+	// The partitioning reports how much of the end of the message did not fit, which is then cut from the start of
+	// the output instead, until the message fits or there is no output left to cut
+	items, omitted := util.PartitionMessage(message, limit, splitDistance)
+	output := []rune(outcome.Output)
+	for omitted > 0 && len(output) > 0 {
+		cut := util.Min(len(output), omitted+len(omittedOutput))
+		output = output[cut:]
+		outcome.Output = omittedOutput + string(output)
+		if message, err = render(t.messageTemplate, outcome); err != nil {
+			return nil, err
+		}
+		items, omitted = util.PartitionMessage(message, limit, splitDistance)
+	}
+
+	timestamp := time.Now()
+	for i := range items {
+		items[i].Level = outcome.Level()
+		items[i].Timestamp = timestamp
+	}
+	return items, nil
+}
+
+func render(tpl *template.Template, outcome Outcome) (string, error) {
+	sb := strings.Builder{}
+	if err := tpl.Execute(&sb, outcome); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// lineTail is an io.Writer keeping the last lines written to it
+type lineTail struct {
+	mutex   sync.Mutex
+	max     int
+	lines   []string
+	partial []byte
+}
+
+// maxLineLength is the number of bytes kept of lines longer than that
+const maxLineLength = 64 * 1024
+
+func newLineTail(maxLines int) *lineTail {
+	return &lineTail{max: maxLines}
+}
+
+func (lt *lineTail) Write(p []byte) (int, error) {
+	lt.mutex.Lock()
+	defer lt.mutex.Unlock()
+
+	lt.partial = append(lt.partial, p...)
+	for {
+		end := bytes.IndexByte(lt.partial, '\n')
+		if end < 0 {
+			break
+		}
+		lt.addLine(string(lt.partial[:end]))
+		lt.partial = lt.partial[end+1:]
+	}
+	if len(lt.partial) > maxLineLength {
+		lt.partial = lt.partial[len(lt.partial)-maxLineLength:]
+	}
+
+	return len(p), nil
+}
+
+func (lt *lineTail) addLine(line string) {
+	if lt.max <= 0 {
+		return
+	}
+	if len(line) > maxLineLength {
+		line = line[len(line)-maxLineLength:]
+	}
+	lt.lines = append(lt.lines, line)
+	if len(lt.lines) > lt.max {
+		lt.lines = lt.lines[len(lt.lines)-lt.max:]
+	}
+}
+
+// String returns the kept lines, including the last line even if it did not end with a newline
+func (lt *lineTail) String() string {
+	lt.mutex.Lock()
+	defer lt.mutex.Unlock()
+
+	lines := append([]string{}, lt.lines...)
+	if len(lt.partial) > 0 && lt.max > 0 {
+		lines = append(lines, string(lt.partial))
+		if len(lines) > lt.max {
+			lines = lines[len(lines)-lt.max:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// stateChanged returns whether the success of the outcome differs from the one stored in the state file, and then
+// stores it. A missing state file is treated as a previous success, so that only failures are reported on the first run.
+func stateChanged(path string, outcome Outcome) (bool, error) {
+	previous := true
+	if content, err := os.ReadFile(path); err == nil {
+		previous = strings.TrimSpace(string(content)) != "failure"
+	} else if !os.IsNotExist(err) {
+		return false, err
+	}
+
+	state := "success"
+	if !outcome.Success {
+		state = "failure"
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(path, []byte(state+"\n"), 0o600); err != nil {
+		return false, err
+	}
+
+	return previous != outcome.Success, nil
+}
@@ -0,0 +1,122 @@
+package send
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+	"unicode"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// Input formats of the message
+const (
+	InputText = "text"
+	InputJSON = "json"
+)
+
+// inputEvent is an event read from the JSON input, sent as a message item
+type inputEvent struct {
+	Text      string            `json:"text"`
+	Level     string            `json:"level"`
+	Timestamp time.Time         `json:"timestamp"`
+	Fields    map[string]string `json:"fields"`
+	Params    map[string]string `json:"params"`
+}
+
+// itemBatch is a run of consecutive events with the same params, sent as a single notification
+type itemBatch struct {
+	items  []types.MessageItem
+	params types.Params
+}
+
+// readEvents reads the events from the JSON input, which is either a single event, an array of events, or a stream of
+// events like NDJSON
+func readEvents(reader io.Reader) ([]inputEvent, error) {
+	buffered := bufio.NewReader(reader)
+	first, err := firstNonSpace(buffered)
+	if err == io.EOF {
+		return nil, errors.New("the input does not contain any events")
+	} else if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(buffered)
+	if first == '[' {
+		var events []inputEvent
+		if err := decoder.Decode(&events); err != nil {
+			return nil, fmt.Errorf("invalid events: %w", err)
+		}
+		if len(events) == 0 {
+			return nil, errors.New("the input does not contain any events")
+		}
+		return events, nil
+	}
+
+	var events []inputEvent
+	for {
+		var event inputEvent
+		if err := decoder.Decode(&event); err == io.EOF {
+			return events, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid event %d: %w", len(events)+1, err)
+		}
+		events = append(events, event)
+	}
+}
+
+// firstNonSpace returns the first byte of the input that is not whitespace, without consuming it
+func firstNonSpace(reader *bufio.Reader) (byte, error) {
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if !unicode.IsSpace(rune(b)) {
+			return b, reader.UnreadByte()
+		}
+	}
+}
+
+// batchEvents converts the events to message items, grouping consecutive events with the same params into a batch.
+// The params of the events are added to a copy of the base params, replacing the ones with the same key.
+func batchEvents(events []inputEvent, base types.Params) ([]itemBatch, error) {
+	var batches []itemBatch
+	for i, event := range events {
+		if event.Text == "" {
+			return nil, fmt.Errorf("invalid event %d: the text is not set", i+1)
+		}
+		level := types.Unknown
+		if event.Level != "" {
+			var err error
+			if level, err = types.ParseMessageLevel(event.Level); err != nil {
+				return nil, fmt.Errorf("invalid event %d: %w", i+1, err)
+			}
+		}
+		item := types.MessageItem{
+			Text:      event.Text,
+			Timestamp: event.Timestamp,
+			Level:     level,
+			Fields:    types.FieldsFromMap(event.Fields, true),
+		}
+
+		params := make(types.Params, len(base)+len(event.Params))
+		for key, value := range base {
+			params[key] = value
+		}
+		for key, value := range event.Params {
+			params[key] = value
+		}
+
+		if last := len(batches) - 1; last >= 0 && reflect.DeepEqual(batches[last].params, params) {
+			batches[last].items = append(batches[last].items, item)
+		} else {
+			batches = append(batches, itemBatch{items: []types.MessageItem{item}, params: params})
+		}
+	}
+	return batches, nil
+}
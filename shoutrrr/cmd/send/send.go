@@ -1,16 +1,21 @@
 package send
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/cobra"
 
 	"github.com/containrrr/shoutrrr/internal/dedupe"
 	intutil "github.com/containrrr/shoutrrr/internal/util"
+	"github.com/containrrr/shoutrrr/pkg/metrics"
 	"github.com/containrrr/shoutrrr/pkg/router"
 	"github.com/containrrr/shoutrrr/pkg/types"
 	"github.com/containrrr/shoutrrr/pkg/util"
@@ -32,10 +37,18 @@ func init() {
 	Cmd.Flags().StringArrayP("url", "u", []string{}, "The notification url")
 	_ = Cmd.MarkFlagRequired("url")
 
+	// The message is only required for the text input format, which is checked when running the command
 	Cmd.Flags().StringP("message", "m", "", "The message to send to the notification url, or - to read message from stdin")
-	_ = Cmd.MarkFlagRequired("message")
+	Cmd.Flags().String("input-format", InputText,
+		"The format of the message: text, or json for a JSON event, an array of events or NDJSON (defaults to stdin)")
 
 	Cmd.Flags().StringP("title", "t", "", "The title used for services that support it")
+	Cmd.Flags().StringArrayP("param", "p", []string{}, "A param passed to the services, in key=value format")
+	Cmd.Flags().StringArray("template", []string{}, "A template loaded into the services, in id=path format")
+	Cmd.Flags().StringArray("template-file", []string{},
+		"A template loaded into the services, using the file name without extension as the id")
+
+	Cmd.Flags().String("metrics-file", "", "Write the delivery metrics to this file, in the Prometheus text format")
 }
 
 func logf(format string, a ...interface{}) {
@@ -50,6 +63,33 @@ func run(cmd *cobra.Command) error {
 	urls = dedupe.RemoveDuplicates(urls)
 	message, _ := flags.GetString("message")
 	title, _ := flags.GetString("title")
+	paramFlags, _ := flags.GetStringArray("param")
+	templateFlags, _ := flags.GetStringArray("template")
+	templateFileFlags, _ := flags.GetStringArray("template-file")
+	metricsFile, _ := flags.GetString("metrics-file")
+	inputFormat, _ := flags.GetString("input-format")
+
+	switch inputFormat {
+	case InputText:
+		if message == "" {
+			return cli.InvalidUsage(`required flag(s) "message" not set`)
+		}
+	case InputJSON:
+		if message == "" {
+			message = "-"
+		}
+	default:
+		return cli.InvalidUsage(fmt.Sprintf("invalid input format %q, expected text or json", inputFormat))
+	}
+
+	params, err := parseParams(paramFlags, title)
+	if err != nil {
+		return err
+	}
+	templates, err := parseTemplates(templateFlags, templateFileFlags)
+	if err != nil {
+		return err
+	}
 
 	if message == "-" {
 		logf("Reading from STDIN...")
@@ -62,21 +102,46 @@ func run(cmd *cobra.Command) error {
 		message = sb.String()
 	}
 
+	var batches []itemBatch
+	if inputFormat == InputJSON {
+		events, err := readEvents(strings.NewReader(message))
+		if err != nil {
+			return cli.InvalidUsage(fmt.Sprintf("failed to read the events: %v", err))
+		}
+		if batches, err = batchEvents(events, params); err != nil {
+			return cli.InvalidUsage(fmt.Sprintf("failed to read the events: %v", err))
+		}
+	}
+
 	var logger *log.Logger
 
 	if verbose {
 		urlsPrefix := "URLs:"
 		for i, url := range urls {
-			logf("%s %s", urlsPrefix, url)
+			logf("%s %s", urlsPrefix, router.RedactURL(url))
 			if i == 0 {
 				// Only display "URLs:" prefix for first line, replace with indentation for the the subsequent
 				urlsPrefix = strings.Repeat(" ", len(urlsPrefix))
 			}
 		}
-		logf("Message: %s", util.Ellipsis(message, 100))
+		if inputFormat == InputJSON {
+			logf("Events: %d batch(es)", len(batches))
+		} else {
+			logf("Message: %s", util.Ellipsis(message, 100))
+		}
 		if title != "" {
 			logf("Title: %v", title)
 		}
+		keys := make([]string, 0, len(params))
+		for key := range params {
+			if key != types.TitleKey {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			logf("Param: %v=%v", key, params[key])
+		}
 		logger = log.New(os.Stderr, "SHOUTRRR ", log.LstdFlags)
 	} else {
 		logger = util.DiscardLogger
@@ -86,22 +151,123 @@ func run(cmd *cobra.Command) error {
 	if err != nil {
 		return cli.ConfigurationError(fmt.Sprintf("error invoking send: %s", err))
 	} else {
-		params := make(types.Params)
-		if title != "" {
-			params["title"] = title
+		if metricsFile != "" {
+			collector := metrics.NewCollector()
+			sr.AddObserver(collector)
+			defer writeMetrics(metricsFile, collector)
 		}
-		errs := sr.SendAsync(message, &params)
-		for err := range errs {
-			if err != nil {
-				return cli.TaskUnavailable(err.Error())
+
+		for _, tpl := range templates {
+			if err := sr.SetTemplateFile(tpl.id, tpl.path); err != nil {
+				return cli.ConfigurationError(fmt.Sprintf("error invoking send: %s", err))
 			}
-			logf("Notification sent")
+		}
+
+		failed := 0
+		report := func(result router.Result) {
+			if result.Failed() {
+				failed++
+			}
+			for _, fallback := range fallbacks(result) {
+				logf("%s", fallback)
+			}
+			if verbose {
+				logf("%v (%v, %v)", result, result.URL, result.Duration)
+			} else {
+				logf("%v", result)
+			}
+		}
+
+		notifications := len(urls)
+		if inputFormat == InputJSON {
+			notifications *= len(batches)
+			for _, batch := range batches {
+				for _, result := range sr.SendItemsResults(context.Background(), batch.items, batch.params) {
+					report(result)
+				}
+			}
+		} else {
+			for result := range sr.SendAsyncResults(context.Background(), message, &params) {
+				report(result)
+			}
+		}
+		if failed > 0 {
+			return cli.TaskUnavailable(fmt.Sprintf("failed to send %d of %d notification(s)", failed, notifications))
 		}
 	}
 
 	return nil
 }
 
+// templateFile is a template to load into the services, identified by id
+type templateFile struct {
+	id   string
+	path string
+}
+
+// parseParams returns the params from the key=value pairs, with the title set if it is not empty
+func parseParams(paramFlags []string, title string) (types.Params, error) {
+	params := make(types.Params, len(paramFlags)+1)
+	for _, paramFlag := range paramFlags {
+		key, value, found := strings.Cut(paramFlag, "=")
+		if !found || key == "" {
+			return nil, cli.InvalidUsage(fmt.Sprintf("invalid param %q, expected a key=value pair", paramFlag))
+		}
+		params[key] = value
+	}
+	if title != "" {
+		params.SetTitle(title)
+	}
+	return params, nil
+}
+
+// parseTemplates returns the templates from the id=path pairs, followed by the template files using the file name
+// without extension as the id
+func parseTemplates(templateFlags []string, templateFileFlags []string) ([]templateFile, error) {
+	templates := make([]templateFile, 0, len(templateFlags)+len(templateFileFlags))
+	for _, templateFlag := range templateFlags {
+		id, path, found := strings.Cut(templateFlag, "=")
+		if !found || id == "" || path == "" {
+			return nil, cli.InvalidUsage(fmt.Sprintf("invalid template %q, expected an id=path pair", templateFlag))
+		}
+		templates = append(templates, templateFile{id: id, path: path})
+	}
+	for _, path := range templateFileFlags {
+		name := filepath.Base(path)
+		id := strings.TrimSuffix(name, filepath.Ext(name))
+		if id == "" || id == "." {
+			return nil, cli.InvalidUsage(fmt.Sprintf("invalid template file %q, the file name is used as the id", path))
+		}
+		templates = append(templates, templateFile{id: id, path: path})
+	}
+	return templates, nil
+}
+
+// fallbacks describes each failed attempt of a failover group, together with the service that was tried next
+func fallbacks(result router.Result) []string {
+	messages := make([]string, 0, len(result.Failures))
+	for i, failure := range result.Failures {
+		next := result.Scheme
+		if i+1 < len(result.Failures) {
+			next = result.Failures[i+1].Scheme
+		}
+		messages = append(messages, fmt.Sprintf("%v, falling back to %v", failure, next))
+	}
+	return messages
+}
+
+// writeMetrics writes the metrics to the file, e.g. for the textfile collector of the Prometheus node exporter
+func writeMetrics(path string, collector *metrics.Collector) {
+	registry := prometheus.NewRegistry()
+	err := registry.Register(collector)
+	if err == nil {
+		err = prometheus.WriteToTextfile(path, registry)
+	}
+	if err != nil {
+		logf("Failed to write metrics: %v", err)
+	}
+}
+
 // Run the send command
 func Run(cmd *cobra.Command, _ []string) error {
 	err := run(cmd)
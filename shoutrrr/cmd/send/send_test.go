@@ -0,0 +1,135 @@
+package send
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/router"
+	"github.com/containrrr/shoutrrr/pkg/types"
+	cli "github.com/containrrr/shoutrrr/shoutrrr/cmd"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSend(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Shoutrrr Send Suite")
+}
+
+var _ = Describe("the send command", func() {
+	Describe("the params", func() {
+		It("should be parsed from key=value pairs", func() {
+			params, err := parseParams([]string{"priority=5", "tags=a,b", "query=x=y", "empty="}, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(params).To(Equal(types.Params{"priority": "5", "tags": "a,b", "query": "x=y", "empty": ""}))
+		})
+		It("should use the title flag over a title param", func() {
+			params, err := parseParams([]string{"title=param"}, "flag")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(params).To(HaveKeyWithValue(types.TitleKey, "flag"))
+		})
+		It("should return a usage error for invalid pairs", func() {
+			_, err := parseParams([]string{"priority"}, "")
+			Expect(err).To(HaveOccurred())
+			Expect(err.(cli.Result).ExitCode).To(Equal(cli.ExUsage))
+			_, err = parseParams([]string{"=5"}, "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("the templates", func() {
+		It("should be parsed from id=path pairs and template files", func() {
+			templates, err := parseTemplates([]string{"payload=/etc/payload.json"}, []string{"templates/plain.tmpl", "HTML"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(templates).To(Equal([]templateFile{
+				{id: "payload", path: "/etc/payload.json"},
+				{id: "plain", path: "templates/plain.tmpl"},
+				{id: "HTML", path: "HTML"},
+			}))
+		})
+		It("should return a usage error for invalid pairs", func() {
+			_, err := parseTemplates([]string{"payload"}, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(err.(cli.Result).ExitCode).To(Equal(cli.ExUsage))
+			_, err = parseTemplates([]string{"payload="}, nil)
+			Expect(err).To(HaveOccurred())
+			_, err = parseTemplates(nil, []string{".tmpl"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("the failover log", func() {
+		It("should name the service tried after each failed attempt", func() {
+			result := router.Result{Scheme: "ntfy", Failures: []router.Result{
+				{Scheme: "telegram", Err: errors.New("timeout")},
+				{Scheme: "slack", Err: errors.New("rate limited")},
+			}}
+			Expect(fallbacks(result)).To(Equal([]string{
+				"telegram failed: timeout, falling back to slack",
+				"slack failed: rate limited, falling back to ntfy",
+			}))
+			Expect(fallbacks(router.Result{Scheme: "ntfy"})).To(BeEmpty())
+		})
+	})
+
+	Describe("the JSON input", func() {
+		It("should read a single event", func() {
+			events, err := readEvents(strings.NewReader(`{"text": "disk full", "level": "error"}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(Equal([]inputEvent{{Text: "disk full", Level: "error"}}))
+		})
+		It("should read an array of events", func() {
+			events, err := readEvents(strings.NewReader(` [{"text": "first"}, {"text": "second"}]`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(2))
+		})
+		It("should read NDJSON events", func() {
+			input := "{\"text\": \"first\", \"timestamp\": \"2022-06-01T12:00:00Z\"}\n\n{\"text\": \"second\"}\n"
+			events, err := readEvents(strings.NewReader(input))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(2))
+			Expect(events[0].Timestamp).To(Equal(time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)))
+			Expect(events[1].Text).To(Equal("second"))
+		})
+		It("should return an error for invalid or missing events", func() {
+			_, err := readEvents(strings.NewReader("{\"text\": \"first\"}\n{\"text\": "))
+			Expect(err).To(MatchError(ContainSubstring("invalid event 2")))
+			_, err = readEvents(strings.NewReader(" \n"))
+			Expect(err).To(HaveOccurred())
+			_, err = readEvents(strings.NewReader("[]"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should batch consecutive events with the same params", func() {
+			events := []inputEvent{
+				{Text: "first", Level: "error", Fields: map[string]string{"host": "a"}},
+				{Text: "second", Params: map[string]string{"priority": "1"}},
+				{Text: "third", Params: map[string]string{"title": "Other"}},
+				{Text: "fourth", Params: map[string]string{"title": "Other"}},
+			}
+			batches, err := batchEvents(events, types.Params{"title": "Events", "priority": "1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(batches).To(HaveLen(2))
+
+			Expect(batches[0].params).To(Equal(types.Params{"title": "Events", "priority": "1"}))
+			Expect(batches[0].items).To(HaveLen(2))
+			Expect(batches[0].items[0]).To(Equal(types.MessageItem{
+				Text:   "first",
+				Level:  types.Error,
+				Fields: []types.Field{{Key: "host", Value: "a"}},
+			}))
+
+			Expect(batches[1].params).To(Equal(types.Params{"title": "Other", "priority": "1"}))
+			Expect(batches[1].items).To(HaveLen(2))
+		})
+		It("should return an error for events without text or with an invalid level", func() {
+			_, err := batchEvents([]inputEvent{{Level: "error"}}, nil)
+			Expect(err).To(MatchError(ContainSubstring("the text is not set")))
+			_, err = batchEvents([]inputEvent{{Text: "first", Level: "critical"}}, nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
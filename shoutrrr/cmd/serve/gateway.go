@@ -0,0 +1,195 @@
+package serve
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/router"
+	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
+)
+
+// NotifyPath is the path prefix of the endpoints accepting notifications, followed by the name of the target
+const NotifyPath = "/v1/notify/"
+
+// HealthPath is the path of the endpoint used for checking that the gateway is running, which requires no token
+const HealthPath = "/healthz"
+
+// maxRequestSize is the maximum size of a notification request body
+const maxRequestSize = 1 << 20
+
+// Gateway is an http.Handler that sends the notifications posted to it using the router of the requested target
+type Gateway struct {
+	token   string
+	targets map[string]*router.ServiceRouter
+	logger  types.StdLogger
+	mux     *http.ServeMux
+}
+
+// NotifyRequest is the body of a notification request. Either the message or the items must be set, but not both.
+type NotifyRequest struct {
+	Message string            `json:"message"`
+	Title   string            `json:"title"`
+	Params  map[string]string `json:"params"`
+	Items   []NotifyItem      `json:"items"`
+}
+
+// NotifyItem is an item of a rich notification request
+type NotifyItem struct {
+	Text      string            `json:"text"`
+	Level     string            `json:"level"`
+	Timestamp time.Time         `json:"timestamp"`
+	Fields    map[string]string `json:"fields"`
+}
+
+// NotifyResponse is the body of the response to a notification request
+type NotifyResponse struct {
+	Results []NotifyResult `json:"results,omitempty"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// NotifyResult is the outcome of sending the notification using one of the services of the target
+type NotifyResult struct {
+	Service    string `json:"service"`
+	OK         bool   `json:"ok"`
+	Error      string `json:"error,omitempty"`
+	Skipped    bool   `json:"skipped,omitempty"`
+	Suppressed bool   `json:"suppressed,omitempty"`
+	Attempts   int    `json:"attempts"`
+}
+
+// NewGateway returns a Gateway that accepts the requests authenticated using the bearer token, sending the
+// notifications using the routers of the targets, by their name
+func NewGateway(token string, targets map[string]*router.ServiceRouter, logger types.StdLogger) *Gateway {
+	if logger == nil {
+		logger = util.DiscardLogger
+	}
+	gateway := &Gateway{
+		token:   token,
+		targets: targets,
+		logger:  logger,
+		mux:     http.NewServeMux(),
+	}
+	gateway.mux.HandleFunc(NotifyPath, gateway.handleNotify)
+	gateway.mux.HandleFunc(HealthPath, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("OK\n"))
+	})
+	return gateway
+}
+
+// ServeHTTP implements http.Handler
+func (gateway *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	gateway.mux.ServeHTTP(w, r)
+}
+
+func (gateway *Gateway) handleNotify(w http.ResponseWriter, r *http.Request) {
+	if !gateway.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeResponse(w, http.StatusUnauthorized, NotifyResponse{Error: "missing or invalid token"})
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeResponse(w, http.StatusMethodNotAllowed, NotifyResponse{Error: "only POST is allowed"})
+		return
+	}
+
+	targetName := strings.TrimPrefix(r.URL.Path, NotifyPath)
+	target, found := gateway.targets[targetName]
+	if !found {
+		writeResponse(w, http.StatusNotFound, NotifyResponse{Error: fmt.Sprintf("unknown target %q", targetName)})
+		return
+	}
+
+	var request NotifyRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		writeResponse(w, http.StatusBadRequest, NotifyResponse{Error: fmt.Sprintf("invalid request body: %v", err)})
+		return
+	}
+
+	results, err := send(r, target, request)
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, NotifyResponse{Error: err.Error()})
+		return
+	}
+
+	response := NotifyResponse{Results: make([]NotifyResult, len(results))}
+	status := http.StatusOK
+	for i, result := range results {
+		response.Results[i] = NotifyResult{
+			Service:    result.Scheme,
+			OK:         !result.Failed(),
+			Skipped:    result.Skipped,
+			Suppressed: result.Suppressed,
+			Attempts:   result.Attempts,
+		}
+		if result.Failed() {
+			response.Results[i].Error = result.Err.Error()
+			status = http.StatusBadGateway
+		}
+		gateway.logger.Printf("%s: %v", targetName, result)
+	}
+	writeResponse(w, status, response)
+}
+
+// authorized returns whether the request has the bearer token of the gateway, which must not be empty.
+// The Bearer scheme is required, but its case is not significant
+func (gateway *Gateway) authorized(r *http.Request) bool {
+	if gateway.token == "" {
+		return false
+	}
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(gateway.token)) == 1
+}
+
+// send sends the notification of the request using the target router, aborting if the client goes away
+func send(r *http.Request, target *router.ServiceRouter, request NotifyRequest) ([]router.Result, error) {
+	params := types.Params{}
+	for key, value := range request.Params {
+		params[key] = value
+	}
+	if request.Title != "" {
+		params.SetTitle(request.Title)
+	}
+
+	if (request.Message == "") == (len(request.Items) == 0) {
+		return nil, errors.New("either the message or the items must be set")
+	}
+	if request.Message != "" {
+		return target.SendResults(r.Context(), request.Message, &params), nil
+	}
+
+	items := make([]types.MessageItem, len(request.Items))
+	for i, item := range request.Items {
+		level := types.Unknown
+		if item.Level != "" {
+			var err error
+			if level, err = types.ParseMessageLevel(item.Level); err != nil {
+				return nil, err
+			}
+		}
+		items[i] = types.MessageItem{
+			Text:      item.Text,
+			Timestamp: item.Timestamp,
+			Level:     level,
+			Fields:    types.FieldsFromMap(item.Fields, true),
+		}
+	}
+	return target.SendItemsResults(r.Context(), items, params), nil
+}
+
+func writeResponse(w http.ResponseWriter, status int, response NotifyResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,201 @@
+package serve
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+
+	"github.com/containrrr/shoutrrr/pkg/router"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestServe(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Shoutrrr Serve Suite")
+}
+
+var _ = Describe("the notification gateway", func() {
+	var gateway *Gateway
+	var requestsMutex sync.Mutex
+	var requests []string
+
+	// recorded returns the bodies of the requests made to the mocked services, which are recorded concurrently
+	recorded := func() []string {
+		requestsMutex.Lock()
+		defer requestsMutex.Unlock()
+		return append([]string{}, requests...)
+	}
+
+	newTarget := func(urls ...string) *router.ServiceRouter {
+		client := &http.Client{Transport: &http.Transport{}}
+		httpmock.ActivateNonDefault(client)
+		target, err := router.New(nil, urls...)
+		Expect(err).NotTo(HaveOccurred())
+		target.SetHTTPClient(client)
+		target.RetryPolicy = router.RetryPolicy{MaxAttempts: 1}
+		return target
+	}
+
+	post := func(path string, token string, body string) (*http.Response, NotifyResponse) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		recorder := httptest.NewRecorder()
+		gateway.ServeHTTP(recorder, req)
+
+		var response NotifyResponse
+		Expect(json.NewDecoder(recorder.Body).Decode(&response)).To(Succeed())
+		return recorder.Result(), response
+	}
+
+	BeforeEach(func() {
+		requests = nil
+		record := func(status int) httpmock.Responder {
+			return func(req *http.Request) (*http.Response, error) {
+				body, _ := io.ReadAll(req.Body)
+				requestsMutex.Lock()
+				requests = append(requests, string(body))
+				requestsMutex.Unlock()
+				return httpmock.NewStringResponse(status, ""), nil
+			}
+		}
+		ops := newTarget("generic://ops.example.com/hook?template=json")
+		mixed := newTarget("generic://ops.example.com/hook", "generic://down.example.com/hook")
+		httpmock.RegisterResponder("POST", "https://ops.example.com/hook", record(200))
+		httpmock.RegisterResponder("POST", "https://down.example.com/hook", record(503))
+
+		gateway = NewGateway("secret", map[string]*router.ServiceRouter{"ops": ops, "mixed": mixed}, nil)
+	})
+	AfterEach(func() {
+		httpmock.DeactivateAndReset()
+	})
+
+	It("should reject requests without a valid token", func() {
+		res, response := post("/v1/notify/ops", "", `{"message": "hello"}`)
+		Expect(res.StatusCode).To(Equal(http.StatusUnauthorized))
+		Expect(response.Error).NotTo(BeEmpty())
+
+		res, _ = post("/v1/notify/ops", "wrong", `{"message": "hello"}`)
+		Expect(res.StatusCode).To(Equal(http.StatusUnauthorized))
+		Expect(recorded()).To(BeEmpty())
+	})
+	It("should require the Bearer scheme in the authorization header", func() {
+		authorize := func(header string) int {
+			req := httptest.NewRequest(http.MethodPost, "/v1/notify/ops", strings.NewReader(`{"message": "hello"}`))
+			req.Header.Set("Authorization", header)
+			recorder := httptest.NewRecorder()
+			gateway.ServeHTTP(recorder, req)
+			return recorder.Code
+		}
+		Expect(authorize("secret")).To(Equal(http.StatusUnauthorized))
+		Expect(authorize("Basic secret")).To(Equal(http.StatusUnauthorized))
+		Expect(authorize("Bearersecret")).To(Equal(http.StatusUnauthorized))
+		Expect(authorize("bearer secret")).To(Equal(http.StatusOK))
+		Expect(authorize("BEARER secret")).To(Equal(http.StatusOK))
+	})
+	It("should reject every request if the token is empty", func() {
+		gateway = NewGateway("", gateway.targets, nil)
+		res, _ := post("/v1/notify/ops", "", `{"message": "hello"}`)
+		Expect(res.StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+	It("should send the message and title using the target", func() {
+		res, response := post("/v1/notify/ops", "secret", `{"message": "hello", "title": "greeting"}`)
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+		Expect(response.Results).To(Equal([]NotifyResult{{Service: "generic", OK: true, Attempts: 1}}))
+		Expect(recorded()).To(HaveLen(1))
+		Expect(recorded()[0]).To(MatchJSON(`{"message": "hello", "title": "greeting"}`))
+	})
+	It("should send the items using the target", func() {
+		res, _ := post("/v1/notify/ops", "secret", `{"items": [
+			{"text": "disk full", "level": "error", "fields": {"host": "db1"}},
+			{"text": "retrying", "level": "info"}
+		]}`)
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+		Expect(recorded()).To(HaveLen(1))
+		Expect(recorded()[0]).To(ContainSubstring("disk full"))
+		Expect(recorded()[0]).To(ContainSubstring("retrying"))
+	})
+	It("should not apply the params of a request to later requests", func() {
+		var titles []string
+		ntfy := newTarget("ntfy://ntfy.example.com/alerts")
+		httpmock.RegisterResponder("POST", "https://ntfy.example.com/alerts",
+			func(req *http.Request) (*http.Response, error) {
+				requestsMutex.Lock()
+				titles = append(titles, req.Header.Get("Title"))
+				requestsMutex.Unlock()
+				return httpmock.NewStringResponse(200, "{}"), nil
+			})
+		gateway = NewGateway("secret", map[string]*router.ServiceRouter{"ntfy": ntfy}, nil)
+
+		res, _ := post("/v1/notify/ntfy", "secret", `{"message": "first", "params": {"title": "Outage", "priority": "5"}}`)
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+		res, _ = post("/v1/notify/ntfy", "secret", `{"message": "second"}`)
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+
+		requestsMutex.Lock()
+		defer requestsMutex.Unlock()
+		Expect(titles).To(Equal([]string{"Outage", ""}))
+	})
+	It("should report the services that failed", func() {
+		res, response := post("/v1/notify/mixed", "secret", `{"message": "hello"}`)
+		Expect(res.StatusCode).To(Equal(http.StatusBadGateway))
+		Expect(response.Results).To(HaveLen(2))
+		Expect(response.Results[0].OK).To(BeTrue())
+		Expect(response.Results[1].OK).To(BeFalse())
+		Expect(response.Results[1].Error).To(ContainSubstring("503"))
+	})
+	It("should return an error for unknown targets", func() {
+		res, response := post("/v1/notify/unknown", "secret", `{"message": "hello"}`)
+		Expect(res.StatusCode).To(Equal(http.StatusNotFound))
+		Expect(response.Error).To(ContainSubstring(`unknown target "unknown"`))
+	})
+	It("should return an error for invalid requests", func() {
+		for _, body := range []string{
+			`not json`,
+			`{"message": "hello", "unknown": true}`,
+			`{}`,
+			`{"message": "hello", "items": [{"text": "hello"}]}`,
+			`{"items": [{"text": "hello", "level": "critical"}]}`,
+		} {
+			res, response := post("/v1/notify/ops", "secret", body)
+			Expect(res.StatusCode).To(Equal(http.StatusBadRequest), body)
+			Expect(response.Error).NotTo(BeEmpty())
+		}
+		Expect(recorded()).To(BeEmpty())
+	})
+	It("should only accept POST requests", func() {
+		req := httptest.NewRequest(http.MethodGet, "/v1/notify/ops", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		recorder := httptest.NewRecorder()
+		gateway.ServeHTTP(recorder, req)
+		Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+	It("should respond to health checks without a token", func() {
+		recorder := httptest.NewRecorder()
+		gateway.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, HealthPath, nil))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+	})
+})
+
+var _ = Describe("the target flags", func() {
+	It("should group the URLs by the target name", func() {
+		targets, err := parseTargets([]string{"ops=logger://", "dev=logger://", "ops=logger://"}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targetNames(targets)).To(Equal([]string{"dev", "ops"}))
+	})
+	It("should return an error for invalid targets", func() {
+		_, err := parseTargets([]string{"logger://"}, nil)
+		Expect(err).To(HaveOccurred())
+		_, err = parseTargets([]string{"ops=unknown://"}, nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
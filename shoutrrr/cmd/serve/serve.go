@@ -0,0 +1,146 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"regexp"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/containrrr/shoutrrr/pkg/router"
+	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
+	cli "github.com/containrrr/shoutrrr/shoutrrr/cmd"
+)
+
+// TokenEnv is the environment variable the token is read from, if it is not set using the flag
+const TokenEnv = "SHOUTRRR_SERVE_TOKEN"
+
+// targetNamePattern matches valid target names, which are used in the endpoint paths
+var targetNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// shutdownTimeout is how long the requests being handled are waited for when the server is stopped
+const shutdownTimeout = 30 * time.Second
+
+// Cmd runs an HTTP server relaying the notifications posted to it
+var Cmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run an HTTP gateway that sends the notifications posted to it using the configured service urls",
+	Args:  cobra.NoArgs,
+	RunE:  Run,
+}
+
+func init() {
+	Cmd.Flags().BoolP("verbose", "v", false, "")
+
+	Cmd.Flags().StringP("listen", "l", ":8080", "The address to listen on")
+	Cmd.Flags().StringArrayP("target", "t", []string{}, "A target and one of its notification urls, in name=url format")
+	_ = Cmd.MarkFlagRequired("target")
+
+	Cmd.Flags().String("token", "", "The bearer token required by the notify endpoints, defaults to $"+TokenEnv)
+}
+
+func run(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	verbose, _ := flags.GetBool("verbose")
+	listen, _ := flags.GetString("listen")
+	targetFlags, _ := flags.GetStringArray("target")
+
+	token, _ := flags.GetString("token")
+	if token == "" {
+		token = os.Getenv(TokenEnv)
+	}
+	if token == "" {
+		return cli.InvalidUsage(fmt.Sprintf("a token is required, set it using --token or %s", TokenEnv))
+	}
+
+	var logger types.StdLogger = util.DiscardLogger
+	if verbose {
+		logger = log.New(os.Stderr, "SHOUTRRR ", log.LstdFlags)
+	}
+
+	targets, err := parseTargets(targetFlags, logger)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:              listen,
+		Handler:           NewGateway(token, targets, log.New(os.Stderr, "", log.LstdFlags)),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(os.Stderr, "Listening on %s, with the targets: %s\n", listen, strings.Join(targetNames(targets), ", "))
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return cli.TaskUnavailable(fmt.Sprintf("error running server: %s", err))
+	}
+
+	return nil
+}
+
+// parseTargets creates a router for each target, with the URLs given for it
+func parseTargets(targetFlags []string, logger types.StdLogger) (map[string]*router.ServiceRouter, error) {
+	targets := map[string]*router.ServiceRouter{}
+
+	for _, targetFlag := range targetFlags {
+		name, serviceURL, found := strings.Cut(targetFlag, "=")
+		if !found || !targetNamePattern.MatchString(name) {
+			return nil, cli.InvalidUsage(fmt.Sprintf("invalid target %q, expected a name=url pair", router.RedactURL(targetFlag)))
+		}
+
+		target, exists := targets[name]
+		if !exists {
+			var err error
+			if target, err = router.New(logger); err != nil {
+				return nil, cli.ConfigurationError(fmt.Sprintf("error creating target %q: %s", name, err))
+			}
+			targets[name] = target
+		}
+
+		if err := target.AddService(serviceURL); err != nil {
+			return nil, cli.ConfigurationError(fmt.Sprintf("error adding service to target %q: %s", name, err))
+		}
+	}
+
+	return targets, nil
+}
+
+func targetNames(targets map[string]*router.ServiceRouter) []string {
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run the serve command
+func Run(cmd *cobra.Command, _ []string) error {
+	err := run(cmd)
+	if err != nil {
+		if result, ok := err.(cli.Result); ok && result.ExitCode != cli.ExUsage {
+			// If the error is not related to the CLI usage, report error and exit to not invoke cobra error output
+			_, _ = fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(result.ExitCode)
+		}
+	}
+	return err
+}
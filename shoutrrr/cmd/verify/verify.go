@@ -25,11 +25,14 @@ var sr router.ServiceRouter
 func init() {
 	Cmd.Flags().StringP("url", "u", "", "The notification url")
 	_ = Cmd.MarkFlagRequired("url")
+
+	Cmd.Flags().Bool("show-secrets", false, "Show the values of secret fields, like passwords and tokens")
 }
 
 // Run the verify command
 func Run(cmd *cobra.Command, _ []string) {
 	URL, _ := cmd.Flags().GetString("url")
+	showSecrets, _ := cmd.Flags().GetBool("show-secrets")
 	sr = router.ServiceRouter{}
 
 	service, err := sr.Locate(URL)
@@ -41,6 +44,9 @@ func Run(cmd *cobra.Command, _ []string) {
 
 	config := format.GetServiceConfig(service)
 	configNode := format.GetConfigFormat(config)
+	if !showSecrets {
+		format.RedactConfigFormat(configNode)
+	}
 
 	_, _ = fmt.Fprint(color.Output, format.ColorFormatTree(configNode, true))
 }
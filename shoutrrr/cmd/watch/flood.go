@@ -0,0 +1,53 @@
+package watch
+
+import (
+	"time"
+)
+
+// floodGuard limits the number of lines sent within a window, so that a burst of matching lines, like a crash loop,
+// does not cause a flood of notifications. The number of suppressed lines is reported once the window has passed.
+type floodGuard struct {
+	limit      int
+	window     time.Duration
+	start      time.Time
+	count      int
+	suppressed int
+}
+
+func newFloodGuard(limit int, window time.Duration) *floodGuard {
+	return &floodGuard{limit: limit, window: window}
+}
+
+// allow returns whether a line matched at now may be sent, counting it as suppressed otherwise
+func (g *floodGuard) allow(now time.Time) bool {
+	if g.limit <= 0 {
+		return true
+	}
+	if g.start.IsZero() {
+		g.start = now
+	}
+	if g.count >= g.limit {
+		g.suppressed++
+		return false
+	}
+	g.count++
+	return true
+}
+
+// rollover starts a new window if the current one has passed at now, returning the number of lines that were
+// suppressed in it
+func (g *floodGuard) rollover(now time.Time) int {
+	if g.start.IsZero() || now.Sub(g.start) < g.window {
+		return 0
+	}
+	return g.reset()
+}
+
+// reset starts a new window right away, returning the number of lines that were suppressed in the current one
+func (g *floodGuard) reset() int {
+	suppressed := g.suppressed
+	g.start = time.Time{}
+	g.count = 0
+	g.suppressed = 0
+	return suppressed
+}
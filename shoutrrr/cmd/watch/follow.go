@@ -0,0 +1,200 @@
+package watch
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// StdinSource is the source name used to read lines from stdin
+const StdinSource = "-"
+
+// maxLineLength is the number of bytes kept of lines longer than that
+const maxLineLength = 64 * 1024
+
+// pollInterval is how often the followed files are checked for new lines, rotation and truncation
+var pollInterval = 250 * time.Millisecond
+
+// line is a line read from one of the sources
+type line struct {
+	source string
+	text   string
+}
+
+// followReader sends the lines read from reader to lines, until it is exhausted or ctx is done. Lines longer than
+// maxLineLength are truncated, like the lines of followed files.
+func followReader(ctx context.Context, reader io.Reader, source string, lines chan<- line) error {
+	buffered := bufio.NewReader(reader)
+	text := make([]byte, 0, 4096)
+	for {
+		fragment, err := buffered.ReadSlice('\n')
+		if room := maxLineLength - len(text); room > 0 {
+			if len(fragment) > room {
+				fragment = fragment[:room]
+			}
+			text = append(text, fragment...)
+		}
+
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		} else if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		if len(text) > 0 {
+			select {
+			case lines <- line{source: source, text: strings.TrimRight(string(text), "\r\n")}:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		if err != nil {
+			return nil
+		}
+		text = text[:0]
+	}
+}
+
+// fileFollower reads the lines appended to a file like `tail -F`, reopening the file if it is replaced, e.g. by log
+// rotation, and starting over if it is truncated
+type fileFollower struct {
+	path    string
+	file    *os.File
+	reader  *bufio.Reader
+	offset  int64
+	partial string
+}
+
+// followFile sends the lines appended to the file at path to lines, until ctx is done. If fromStart is set, the lines
+// already in the file are sent as well. A missing file is waited for.
+func followFile(ctx context.Context, path string, fromStart bool, lines chan<- line) error {
+	f := &fileFollower{path: path}
+	defer f.close()
+
+	if err := f.open(!fromStart); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	for {
+		if f.file == nil {
+			// Files that appear after the start are read from the beginning, since every line in them is new
+			if err := f.open(false); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+
+		if f.file != nil {
+			if err := f.readLines(ctx, lines); err != nil {
+				return err
+			}
+			if err := f.checkReplaced(ctx, lines); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func (f *fileFollower) open(seekEnd bool) error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+
+	f.offset = 0
+	if seekEnd {
+		if f.offset, err = file.Seek(0, io.SeekEnd); err != nil {
+			_ = file.Close()
+			return err
+		}
+	}
+
+	f.file = file
+	f.reader = bufio.NewReader(file)
+	f.partial = ""
+	return nil
+}
+
+func (f *fileFollower) close() {
+	if f.file != nil {
+		_ = f.file.Close()
+		f.file = nil
+	}
+}
+
+// readLines sends the complete lines that have been appended to the file, keeping an incomplete last line until the
+// rest of it has been written
+func (f *fileFollower) readLines(ctx context.Context, lines chan<- line) error {
+	for {
+		text, err := f.reader.ReadString('\n')
+		f.offset += int64(len(text))
+		f.partial += text
+		if len(f.partial) > maxLineLength {
+			f.partial = f.partial[:maxLineLength]
+		}
+
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		text = strings.TrimRight(f.partial, "\r\n")
+		f.partial = ""
+		select {
+		case lines <- line{source: f.path, text: text}:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// checkReplaced reopens the file if the path now refers to another file, and starts over if it has been truncated
+func (f *fileFollower) checkReplaced(ctx context.Context, lines chan<- line) error {
+	pathInfo, err := os.Stat(f.path)
+	if err != nil {
+		// The file has been moved without being replaced yet, keep following it until it is
+		return nil
+	}
+	fileInfo, err := f.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if !os.SameFile(pathInfo, fileInfo) {
+		// The file has been rotated, send the last line of it even if it was not terminated, and read the new file
+		if f.partial != "" {
+			select {
+			case lines <- line{source: f.path, text: strings.TrimRight(f.partial, "\r\n")}:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		f.close()
+		if err := f.open(false); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	if pathInfo.Size() < f.offset {
+		// The file has been truncated, e.g. by logrotate with copytruncate
+		if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		f.reader.Reset(f.file)
+		f.offset = 0
+		f.partial = ""
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package watch
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/containrrr/shoutrrr/pkg/types"
+)
+
+// rule matches lines using a regular expression, and sets the level of the message items for them
+type rule struct {
+	pattern *regexp.Regexp
+	level   types.MessageLevel
+}
+
+// parseRule parses a rule in the format [level:]regex, using defaultLevel if the level is omitted
+func parseRule(value string, defaultLevel types.MessageLevel) (rule, error) {
+	level := defaultLevel
+	expr := value
+	if prefix, rest, found := strings.Cut(value, ":"); found {
+		// Regular expressions containing a colon can still be used without a level, as long as the part before
+		// the colon is not a level name
+		if parsed, err := types.ParseMessageLevel(prefix); err == nil {
+			level = parsed
+			expr = rest
+		}
+	}
+
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return rule{}, fmt.Errorf("invalid rule %q: %w", value, err)
+	}
+	return rule{pattern: pattern, level: level}, nil
+}
+
+// match returns the level of the first rule matching the text, or false if none of them does
+func match(rules []rule, text string) (types.MessageLevel, bool) {
+	for _, r := range rules {
+		if r.pattern.MatchString(text) {
+			return r.level, true
+		}
+	}
+	return types.Unknown, false
+}
@@ -0,0 +1,247 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/containrrr/shoutrrr/internal/dedupe"
+	"github.com/containrrr/shoutrrr/pkg/logsink"
+	"github.com/containrrr/shoutrrr/pkg/router"
+	"github.com/containrrr/shoutrrr/pkg/types"
+	"github.com/containrrr/shoutrrr/pkg/util"
+	cli "github.com/containrrr/shoutrrr/shoutrrr/cmd"
+)
+
+// Cmd follows log files and sends notifications for the lines matching the rules
+var Cmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Follow log files or stdin, and send notifications for the matching lines using the service urls",
+	Args:  cobra.NoArgs,
+	RunE:  Run,
+}
+
+func init() {
+	Cmd.Flags().BoolP("verbose", "v", false, "")
+
+	Cmd.Flags().StringArrayP("url", "u", []string{}, "The notification url")
+	_ = Cmd.MarkFlagRequired("url")
+
+	Cmd.Flags().StringArrayP("file", "f", []string{},
+		"A file to follow across rotations, or - for stdin (defaults to stdin)")
+	Cmd.Flags().Bool("from-start", false, "Match the lines already in the files, instead of only the new ones")
+	Cmd.Flags().StringArrayP("rule", "r", []string{},
+		"A regular expression matching the lines to send, in [level:]regex format (defaults to every line)")
+	Cmd.Flags().String("level", types.Warning.String(), "The level used for rules that do not set one")
+	Cmd.Flags().StringP("title", "t", "", "The title used for services that support it")
+
+	Cmd.Flags().Duration("interval", logsink.DefaultInterval, "The time matching lines are collected for, before sending them")
+	Cmd.Flags().Int("max-items", logsink.DefaultMaxItems, "The number of matching lines that are sent right away")
+	Cmd.Flags().Int("flood-limit", 100, "The maximum number of lines sent within the flood window, or 0 for no limit")
+	Cmd.Flags().Duration("flood-window", time.Minute, "The window used for the flood limit")
+}
+
+// watcher matches the lines from the sources against the rules, and adds the matching ones to the batcher
+type watcher struct {
+	rules   []rule
+	level   types.MessageLevel
+	batcher *logsink.Batcher
+	guard   *floodGuard
+}
+
+// handle adds the line to the batcher if it matches, and the flood limit allows it
+func (w *watcher) handle(l line, now time.Time) {
+	w.checkFlood(now)
+
+	level := w.level
+	if len(w.rules) > 0 {
+		var matched bool
+		if level, matched = match(w.rules, l.text); !matched {
+			return
+		}
+	}
+
+	if !w.guard.allow(now) {
+		return
+	}
+
+	w.batcher.Add(types.MessageItem{
+		Text:      l.text,
+		Timestamp: now,
+		Level:     level,
+		Fields:    []types.Field{{Key: "source", Value: l.source}},
+	})
+}
+
+// checkFlood sends a notice about the lines suppressed by the flood limit once the flood window has passed
+func (w *watcher) checkFlood(now time.Time) {
+	w.reportSuppressed(w.guard.rollover(now), now)
+}
+
+// reportSuppressed sends a notice about the number of lines suppressed by the flood limit, if any
+func (w *watcher) reportSuppressed(suppressed int, now time.Time) {
+	if suppressed > 0 {
+		w.batcher.Add(types.MessageItem{
+			Text:      fmt.Sprintf("Suppressed %d matching line(s) within %v", suppressed, w.guard.window),
+			Timestamp: now,
+			Level:     types.Warning,
+		})
+	}
+}
+
+func run(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	verbose, _ := flags.GetBool("verbose")
+	urls, _ := flags.GetStringArray("url")
+	urls = dedupe.RemoveDuplicates(urls)
+	files, _ := flags.GetStringArray("file")
+	fromStart, _ := flags.GetBool("from-start")
+	ruleValues, _ := flags.GetStringArray("rule")
+	levelName, _ := flags.GetString("level")
+	title, _ := flags.GetString("title")
+	interval, _ := flags.GetDuration("interval")
+	maxItems, _ := flags.GetInt("max-items")
+	floodLimit, _ := flags.GetInt("flood-limit")
+	floodWindow, _ := flags.GetDuration("flood-window")
+
+	defaultLevel, err := types.ParseMessageLevel(levelName)
+	if err != nil {
+		return cli.InvalidUsage(err.Error())
+	}
+	rules := make([]rule, 0, len(ruleValues))
+	for _, value := range ruleValues {
+		r, err := parseRule(value, defaultLevel)
+		if err != nil {
+			return cli.InvalidUsage(err.Error())
+		}
+		rules = append(rules, r)
+	}
+	if len(files) == 0 {
+		files = []string{StdinSource}
+	}
+	files = dedupe.RemoveDuplicates(files)
+
+	var logger types.StdLogger = util.DiscardLogger
+	if verbose {
+		logger = log.New(os.Stderr, "SHOUTRRR ", log.LstdFlags)
+	}
+
+	sr, err := router.New(logger, urls...)
+	if err != nil {
+		return cli.ConfigurationError(fmt.Sprintf("error invoking watch: %s", err))
+	}
+
+	params := types.Params{}
+	if title != "" {
+		params.SetTitle(title)
+	}
+
+	var sendFailed bool
+	var failedMutex sync.Mutex
+	w := &watcher{
+		rules: rules,
+		level: defaultLevel,
+		guard: newFloodGuard(floodLimit, floodWindow),
+		batcher: logsink.NewBatcher(sr, &logsink.Options{
+			Params:   params,
+			Interval: interval,
+			MaxItems: maxItems,
+			ErrorHandler: func(errs []error) {
+				failedMutex.Lock()
+				sendFailed = true
+				failedMutex.Unlock()
+				for _, err := range errs {
+					logf("Failed to send notification: %v", err)
+				}
+			},
+		}),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if failed := w.watch(ctx, files, fromStart); failed > 0 {
+		return cli.TaskUnavailable(fmt.Sprintf("failed to follow %d of %d source(s)", failed, len(files)))
+	}
+	if sendFailed {
+		return cli.TaskUnavailable("failed to send some of the notifications")
+	}
+	return nil
+}
+
+// watch handles the lines of the sources until ctx is done, or every source has stopped, and then sends the remaining
+// notifications. Sources stop when stdin is closed, or when a file could not be followed anymore. It returns the
+// number of sources that could not be followed.
+func (w *watcher) watch(ctx context.Context, sources []string, fromStart bool) int {
+	lines := make(chan line)
+	var failed int32
+	var followers sync.WaitGroup
+	for _, source := range sources {
+		followers.Add(1)
+		go func(source string) {
+			defer followers.Done()
+			var err error
+			if source == StdinSource {
+				err = followReader(ctx, os.Stdin, source, lines)
+			} else {
+				err = followFile(ctx, source, fromStart, lines)
+			}
+			if err != nil {
+				atomic.AddInt32(&failed, 1)
+				logf("Failed to follow %s: %v", source, err)
+			}
+		}(source)
+	}
+	go func() {
+		followers.Wait()
+		close(lines)
+	}()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for done := false; !done; {
+		select {
+		case l, ok := <-lines:
+			if !ok {
+				done = true
+				break
+			}
+			w.handle(l, time.Now())
+		case now := <-ticker.C:
+			w.checkFlood(now)
+		case <-ctx.Done():
+			done = true
+		}
+	}
+
+	// Report the lines suppressed in the current window as well, since the window will not pass anymore
+	w.reportSuppressed(w.guard.reset(), time.Now())
+	w.batcher.Flush()
+
+	return int(atomic.LoadInt32(&failed))
+}
+
+func logf(format string, a ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", a...)
+}
+
+// Run the watch command
+func Run(cmd *cobra.Command, _ []string) error {
+	err := run(cmd)
+	if err != nil {
+		if result, ok := err.(cli.Result); ok && result.ExitCode != cli.ExUsage {
+			// If the error is not related to the CLI usage, report error and exit to not invoke cobra error output
+			_, _ = fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(result.ExitCode)
+		}
+	}
+	return err
+}
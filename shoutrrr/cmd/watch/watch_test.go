@@ -0,0 +1,303 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containrrr/shoutrrr/pkg/logsink"
+	"github.com/containrrr/shoutrrr/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestWatch(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Shoutrrr Watch Suite")
+}
+
+var _ = BeforeSuite(func() {
+	pollInterval = 10 * time.Millisecond
+})
+
+// recordingSender records the items sent using it
+type recordingSender struct {
+	mutex sync.Mutex
+	items []types.MessageItem
+}
+
+func (rs *recordingSender) SendItems(items []types.MessageItem, _ types.Params) []error {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	rs.items = append(rs.items, items...)
+	return nil
+}
+
+func (rs *recordingSender) texts() []string {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	texts := make([]string, 0, len(rs.items))
+	for _, item := range rs.items {
+		texts = append(texts, item.Text)
+	}
+	return texts
+}
+
+var _ = Describe("the watch command", func() {
+	Describe("the rules", func() {
+		It("should use the level prefix of the rule", func() {
+			r, err := parseRule("error:panic|fatal", types.Warning)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.level).To(Equal(types.Error))
+			Expect(r.pattern.String()).To(Equal("panic|fatal"))
+		})
+		It("should use the default level if the prefix is not a level", func() {
+			r, err := parseRule("status: failed", types.Warning)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.level).To(Equal(types.Warning))
+			Expect(r.pattern.String()).To(Equal("status: failed"))
+		})
+		It("should return an error for invalid expressions", func() {
+			_, err := parseRule("error:(", types.Warning)
+			Expect(err).To(HaveOccurred())
+		})
+		It("should use the level of the first matching rule", func() {
+			first, _ := parseRule("error:failed", types.Warning)
+			second, _ := parseRule("info:.*", types.Warning)
+			rules := []rule{first, second}
+
+			level, matched := match(rules, "backup failed")
+			Expect(matched).To(BeTrue())
+			Expect(level).To(Equal(types.Error))
+			level, _ = match(rules, "backup done")
+			Expect(level).To(Equal(types.Info))
+			_, matched = match(rules[:1], "backup done")
+			Expect(matched).To(BeFalse())
+		})
+	})
+
+	Describe("the flood guard", func() {
+		It("should suppress the lines over the limit until the window has passed", func() {
+			start := time.Now()
+			guard := newFloodGuard(2, time.Minute)
+			Expect(guard.allow(start)).To(BeTrue())
+			Expect(guard.allow(start)).To(BeTrue())
+			Expect(guard.allow(start)).To(BeFalse())
+			Expect(guard.allow(start)).To(BeFalse())
+
+			Expect(guard.rollover(start.Add(time.Second))).To(Equal(0))
+			Expect(guard.rollover(start.Add(time.Minute))).To(Equal(2))
+			Expect(guard.allow(start.Add(time.Minute))).To(BeTrue())
+			Expect(guard.allow(start.Add(time.Minute))).To(BeTrue())
+			Expect(guard.allow(start.Add(time.Minute))).To(BeFalse())
+			Expect(guard.reset()).To(Equal(1))
+		})
+		It("should not limit the lines if the limit is 0", func() {
+			guard := newFloodGuard(0, time.Minute)
+			for i := 0; i < 1000; i++ {
+				Expect(guard.allow(time.Now())).To(BeTrue())
+			}
+		})
+	})
+
+	Describe("the watcher", func() {
+		It("should send the matching lines, and a notice about the suppressed ones", func() {
+			sender := &recordingSender{}
+			r, _ := parseRule("error:failed", types.Warning)
+			w := &watcher{
+				rules:   []rule{r},
+				level:   types.Warning,
+				batcher: logsink.NewBatcher(sender, nil),
+				guard:   newFloodGuard(1, time.Minute),
+			}
+
+			start := time.Now()
+			w.handle(line{source: "app.log", text: "started"}, start)
+			w.handle(line{source: "app.log", text: "job failed"}, start)
+			w.handle(line{source: "app.log", text: "job failed again"}, start)
+			w.checkFlood(start.Add(time.Minute))
+			w.batcher.Flush()
+
+			Expect(sender.texts()).To(Equal([]string{"job failed", "Suppressed 1 matching line(s) within 1m0s"}))
+			Expect(sender.items[0].Level).To(Equal(types.Error))
+			Expect(sender.items[0].Fields).To(ConsistOf(types.Field{Key: "source", Value: "app.log"}))
+		})
+
+		Describe("watching files", func() {
+			var sender *recordingSender
+			var w *watcher
+			var path string
+			var cancel context.CancelFunc
+			var failed chan int
+
+			watch := func(sources ...string) {
+				var ctx context.Context
+				ctx, cancel = context.WithCancel(context.Background())
+				results := make(chan int, 1)
+				failed = results
+				go func(w *watcher) {
+					results <- w.watch(ctx, sources, false)
+				}(w)
+				// Give the followers the time to open the files before they are written to
+				time.Sleep(5 * pollInterval)
+			}
+			write := func(path string, flag int, text string) {
+				file, err := os.OpenFile(path, flag|os.O_WRONLY|os.O_CREATE, 0o600)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = file.WriteString(text)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(file.Close()).To(Succeed())
+			}
+
+			BeforeEach(func() {
+				sender = &recordingSender{}
+				w = &watcher{
+					level:   types.Warning,
+					batcher: logsink.NewBatcher(sender, &logsink.Options{Interval: pollInterval}),
+					guard:   newFloodGuard(0, time.Minute),
+				}
+				path = filepath.Join(GinkgoT().TempDir(), "app.log")
+				cancel = func() {}
+			})
+			AfterEach(func() {
+				cancel()
+			})
+
+			It("should send the lines of a file that is renamed and recreated", func() {
+				write(path, os.O_APPEND, "")
+				watch(path)
+				write(path, os.O_APPEND, "before\nunterminated")
+				Eventually(sender.texts).Should(Equal([]string{"before"}))
+
+				Expect(os.Rename(path, path+".1")).To(Succeed())
+				write(path, os.O_APPEND, "after\n")
+				Eventually(sender.texts).Should(Equal([]string{"before", "unterminated", "after"}))
+
+				cancel()
+				Eventually(failed).Should(Receive(Equal(0)))
+			})
+			It("should send the lines of a file that is copied and truncated", func() {
+				write(path, os.O_APPEND, "")
+				watch(path)
+				write(path, os.O_APPEND, "first line\nsecond line\n")
+				Eventually(sender.texts).Should(Equal([]string{"first line", "second line"}))
+
+				content, err := os.ReadFile(path)
+				Expect(err).NotTo(HaveOccurred())
+				write(path+".1", os.O_APPEND, string(content))
+				write(path, os.O_TRUNC, "")
+				write(path, os.O_APPEND, "third\n")
+				Eventually(sender.texts).Should(Equal([]string{"first line", "second line", "third"}))
+				Consistently(sender.texts, 5*pollInterval).Should(HaveLen(3))
+			})
+			It("should stop and report the sources that could not be followed", func() {
+				watch(filepath.Dir(path))
+				Eventually(failed).Should(Receive(Equal(1)))
+			})
+		})
+	})
+
+	Describe("following stdin", func() {
+		It("should send the lines, truncating the ones that are too long", func() {
+			lines := make(chan line, 10)
+			input := "first\r\n" + strings.Repeat("x", maxLineLength+100) + "\n\nlast"
+
+			Expect(followReader(context.Background(), strings.NewReader(input), StdinSource, lines)).To(Succeed())
+			close(lines)
+
+			var texts []string
+			for l := range lines {
+				texts = append(texts, l.text)
+			}
+			Expect(texts).To(Equal([]string{"first", strings.Repeat("x", maxLineLength), "", "last"}))
+		})
+	})
+	Describe("following a file", func() {
+		var path string
+		var lines chan line
+		var cancel context.CancelFunc
+		var stopped chan struct{}
+
+		follow := func(fromStart bool) {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			stopped = make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				defer close(stopped)
+				Expect(followFile(ctx, path, fromStart, lines)).To(Succeed())
+			}()
+			// Give the follower the time to open the file before it is written to
+			time.Sleep(5 * pollInterval)
+		}
+		write := func(flag int, text string) {
+			file, err := os.OpenFile(path, flag|os.O_WRONLY|os.O_CREATE, 0o600)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = file.WriteString(text)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file.Close()).To(Succeed())
+		}
+		next := func() string {
+			var l line
+			Eventually(lines).Should(Receive(&l))
+			return l.text
+		}
+
+		BeforeEach(func() {
+			path = filepath.Join(GinkgoT().TempDir(), "app.log")
+			lines = make(chan line, 100)
+		})
+		AfterEach(func() {
+			cancel()
+			Eventually(stopped).Should(BeClosed())
+		})
+
+		It("should only send the lines appended after it started", func() {
+			write(os.O_APPEND, "old\n")
+			follow(false)
+			write(os.O_APPEND, "new\npart")
+			Expect(next()).To(Equal("new"))
+			Consistently(lines, 5*pollInterval).ShouldNot(Receive())
+			write(os.O_APPEND, "ial\r\n")
+			Expect(next()).To(Equal("partial"))
+		})
+		It("should send the existing lines if requested", func() {
+			write(os.O_APPEND, "old\n")
+			follow(true)
+			Expect(next()).To(Equal("old"))
+		})
+		It("should wait for a missing file", func() {
+			follow(false)
+			write(os.O_APPEND, "first\n")
+			Expect(next()).To(Equal("first"))
+		})
+		It("should follow the file across rotations", func() {
+			write(os.O_APPEND, "")
+			follow(false)
+			write(os.O_APPEND, "before\n")
+			Expect(next()).To(Equal("before"))
+
+			Expect(os.Rename(path, path+".1")).To(Succeed())
+			write(os.O_APPEND, "after\n")
+			Expect(next()).To(Equal("after"))
+		})
+		It("should start over when the file is truncated", func() {
+			write(os.O_APPEND, "")
+			follow(false)
+			write(os.O_APPEND, strings.Repeat("long line\n", 3))
+			for i := 0; i < 3; i++ {
+				Expect(next()).To(Equal("long line"))
+			}
+
+			write(os.O_TRUNC, "")
+			time.Sleep(5 * pollInterval)
+			write(os.O_APPEND, "short\n")
+			Expect(next()).To(Equal("short"))
+		})
+	})
+})
@@ -4,11 +4,15 @@ import (
 	"os"
 
 	"github.com/containrrr/shoutrrr/internal/meta"
+	"github.com/containrrr/shoutrrr/pkg/router"
 	cli "github.com/containrrr/shoutrrr/shoutrrr/cmd"
 	"github.com/containrrr/shoutrrr/shoutrrr/cmd/docs"
+	"github.com/containrrr/shoutrrr/shoutrrr/cmd/exec"
 	"github.com/containrrr/shoutrrr/shoutrrr/cmd/generate"
 	"github.com/containrrr/shoutrrr/shoutrrr/cmd/send"
+	"github.com/containrrr/shoutrrr/shoutrrr/cmd/serve"
 	"github.com/containrrr/shoutrrr/shoutrrr/cmd/verify"
+	"github.com/containrrr/shoutrrr/shoutrrr/cmd/watch"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -25,6 +29,12 @@ func init() {
 	cmd.AddCommand(generate.Cmd)
 	cmd.AddCommand(send.Cmd)
 	cmd.AddCommand(docs.Cmd)
+	cmd.AddCommand(serve.Cmd)
+	cmd.AddCommand(exec.Cmd)
+	cmd.AddCommand(watch.Cmd)
+
+	// The CLI user supplies the service URLs, so they are allowed to reference secrets using commands
+	_ = router.RegisterSecretResolver("cmd", router.CommandSecretResolver)
 }
 
 func main() {